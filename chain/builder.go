@@ -23,6 +23,6 @@ func (b *ChainBuilder) BuildEmbed() *EmbedChain {
 	return b.embedChain
 }
 
-func (b *ChainBuilder) BuildQuery() Chain {
+func (b *ChainBuilder) BuildQuery() ContextChain {
 	return b.queryChain
 }
@@ -1,37 +1,147 @@
 package chain
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Ashank007/docai/chunker"
 	//"github.com/Ashank007/docai/embedder"
 	"github.com/Ashank007/docai/store"
-	//"github.com/Ashank007/docai/types"
+	"github.com/Ashank007/docai/types"
 )
 
+// DefaultBatchSize is the number of chunks EmbedChain embeds and stores
+// together when BatchSize is not set.
+const DefaultBatchSize = 32
+
 type EmbedChain struct {
 	DocName    string
+	Meta       map[string]string // optional: added to the metadata of every chunk; a chunk's own keys win
 	Chunker    chunker.Chunker
 	EmbedFunc  func(string) ([]float32, error)
 	MetaStore  store.MetadataStore
 	VectorDB   store.VectorStore
+
+	// Chunks are embedded and stored BatchSize at a time. EmbedBatchFunc, when
+	// set, embeds a batch in one call instead of calling EmbedFunc for each
+	// chunk, e.g. embedder.OllamaEmbedder.EmbedBatch.
+	EmbedBatchFunc func([]string) ([][]float32, error)
+	BatchSize      int
+	// EmbedBatchContextFunc, when set, is used instead of EmbedBatchFunc and
+	// is passed the context of the run, e.g. embedder.OllamaEmbedder.EmbedBatchContext.
+	EmbedBatchContextFunc func(context.Context, []string) ([][]float32, error)
+
+	// Workers is the number of batches embedded concurrently; 0 means 1.
+	Workers int
+	// Progress, when set, is called after every stored batch with the number
+	// of chunks embedded so far and the number in the document.
+	Progress func(done, total int)
 }
 
 func (e *EmbedChain) Run(input string) (string, error) {
-	chunks, _ := e.Chunker.Chunk(input)
-	for _, chunk := range chunks {
-		id, err := e.MetaStore.SaveChunk(e.DocName, chunk)
+	return e.RunContext(context.Background(), input)
+}
+
+// RunContext is Run with embedding bound to ctx. Cancelling ctx stops the
+// run after the batches in flight; chunks already stored are kept.
+func (e *EmbedChain) RunContext(ctx context.Context, input string) (string, error) {
+	if fc, ok := e.Chunker.(chunker.FamilyChunker); ok {
+		families, err := chunker.ChunkFamiliesContext(ctx, fc, input)
 		if err != nil {
-			return "", fmt.Errorf("failed to save chunk: %w", err)
+			return "", fmt.Errorf("failed to chunk document: %w", err)
 		}
-		vec, err := e.EmbedFunc(chunk.Text)
-		if err != nil {
-			return "", fmt.Errorf("embedding error: %w", err)
+		return e.runFamilies(ctx, families)
+	}
+	chunks, err := chunker.ChunkContext(ctx, e.Chunker, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to chunk document: %w", err)
+	}
+	return e.RunChunksContext(ctx, chunks)
+}
+
+// RunPages chunks each page separately and records the page number on every chunk.
+func (e *EmbedChain) RunPages(pages []string) (string, error) {
+	return e.RunPagesContext(context.Background(), pages)
+}
+
+// RunPagesContext is RunPages with embedding bound to ctx.
+func (e *EmbedChain) RunPagesContext(ctx context.Context, pages []string) (string, error) {
+	if fc, ok := e.Chunker.(chunker.FamilyChunker); ok {
+		var families []chunker.Family
+		for i, page := range pages {
+			pageFamilies, err := chunker.ChunkFamiliesContext(ctx, fc, page)
+			if err != nil {
+				return "", fmt.Errorf("failed to chunk page %d: %w", i+1, err)
+			}
+			for _, family := range pageFamilies {
+				family.Parent.Page = i + 1
+				for j := range family.Children {
+					family.Children[j].Page = i + 1
+				}
+				families = append(families, family)
+			}
 		}
-		err = e.VectorDB.AddVector(id, vec,e.DocName)
-		if err != nil {
-			return "", fmt.Errorf("vector store error: %w", err)
+		return e.runFamilies(ctx, families)
+	}
+	chunks, err := chunker.ChunkPagesContext(ctx, e.Chunker, pages)
+	if err != nil {
+		return "", fmt.Errorf("failed to chunk document: %w", err)
+	}
+	return e.RunChunksContext(ctx, chunks)
+}
+
+// RunChunks saves, embeds and indexes chunks that were already produced by a chunker.
+func (e *EmbedChain) RunChunks(chunks []types.Chunk) (string, error) {
+	return e.RunChunksContext(context.Background(), chunks)
+}
+
+// RunChunksContext is RunChunks with embedding bound to ctx.
+func (e *EmbedChain) RunChunksContext(ctx context.Context, chunks []types.Chunk) (string, error) {
+	entries := make([]entry, len(chunks))
+	for i, chunk := range chunks {
+		entries[i] = entry{chunk: chunk, family: -1}
+	}
+	if err := e.index(ctx, entries); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Document '%s' embedded successfully.", e.DocName), nil
+}
+
+// RunFamilies saves every parent chunk without embedding it, then saves,
+// embeds and indexes its children with their ParentID set, so that a search
+// matches the small children and can return the parent.
+func (e *EmbedChain) RunFamilies(families []chunker.Family) (string, error) {
+	return e.runFamilies(context.Background(), families)
+}
+
+func (e *EmbedChain) runFamilies(ctx context.Context, families []chunker.Family) (string, error) {
+	var entries []entry
+	for i, family := range families {
+		entries = append(entries, entry{chunk: family.Parent, family: i, parent: true})
+		for _, child := range family.Children {
+			entries = append(entries, entry{chunk: child, family: i})
 		}
 	}
+	if err := e.index(ctx, entries); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("Document '%s' embedded successfully.", e.DocName), nil
 }
+
+func (e *EmbedChain) batchSize() int {
+	if e.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return e.BatchSize
+}
+
+// embedText is the text a chunk is embedded as: its heading path, when it has
+// one, followed by the chunk text, so that a chunk under "Install > Linux"
+// matches questions about installing on Linux.
+func embedText(chunk types.Chunk) string {
+	if len(chunk.Headings) == 0 {
+		return chunk.Text
+	}
+	return strings.Join(chunk.Headings, " > ") + "\n\n" + chunk.Text
+}
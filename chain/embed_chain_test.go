@@ -0,0 +1,212 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+// memStore is a MetadataStore that keeps saved chunks in memory, in order.
+type memStore struct {
+	mu     sync.Mutex
+	chunks []types.Chunk
+}
+
+func (s *memStore) Init(string) error { return nil }
+func (s *memStore) SaveChunk(docName string, c types.Chunk) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Source = docName
+	s.chunks = append(s.chunks, c)
+	c.ID = fmt.Sprintf("%d", len(s.chunks))
+	s.chunks[len(s.chunks)-1] = c
+	return int64(len(s.chunks)), nil
+}
+func (s *memStore) GetChunkByID(id int64) (types.Chunk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id < 1 || int(id) > len(s.chunks) {
+		return types.Chunk{}, fmt.Errorf("no chunk %d", id)
+	}
+	return s.chunks[id-1], nil
+}
+func (s *memStore) ListFiles() ([]types.FileMeta, error) { return nil, nil }
+func (s *memStore) DeleteFile(string) error              { return nil }
+func (s *memStore) Close() error                         { return nil }
+
+func TestEmbedText(t *testing.T) {
+	tests := []struct {
+		name  string
+		chunk types.Chunk
+		want  string
+	}{
+		{"no headings", types.Chunk{Text: "Run it."}, "Run it."},
+		{"one heading", types.Chunk{Text: "Run it.", Headings: []string{"Install"}}, "Install\n\nRun it."},
+		{"heading path", types.Chunk{Text: "Run it.", Headings: []string{"Guide", "Install", "Linux"}}, "Guide > Install > Linux\n\nRun it."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := embedText(tt.chunk); got != tt.want {
+				t.Errorf("embedText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunChunksHeadings(t *testing.T) {
+	chunks := []types.Chunk{
+		{Text: "Intro.", Position: 0},
+		{Text: "Run it.", Position: 12, Headings: []string{"Guide", "Install"}},
+	}
+	var mu sync.Mutex
+	var embedded []string
+	ms := &memStore{}
+	ec := &EmbedChain{
+		DocName: "guide",
+		EmbedFunc: func(text string) ([]float32, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			embedded = append(embedded, text)
+			return []float32{1, 0}, nil
+		},
+		MetaStore: ms,
+		VectorDB:  store.NewMemoryVectorStore(),
+	}
+	if _, err := ec.RunChunks(chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	// The heading path is embedded with the text, but the stored text is left as it was.
+	if want := []string{"Intro.", "Guide > Install\n\nRun it."}; !reflect.DeepEqual(embedded, want) {
+		t.Errorf("embedded %q, want %q", embedded, want)
+	}
+	if len(ms.chunks) != len(chunks) {
+		t.Fatalf("stored %d chunks, want %d", len(ms.chunks), len(chunks))
+	}
+	for i, c := range ms.chunks {
+		if c.Text != chunks[i].Text || c.Position != chunks[i].Position || !reflect.DeepEqual(c.Headings, chunks[i].Headings) {
+			t.Errorf("stored chunk %d = %+v, want %+v", i, c, chunks[i])
+		}
+	}
+}
+
+func TestRunFamilies(t *testing.T) {
+	families := []chunker.Family{
+		{
+			Parent:   types.Chunk{Text: "One. Two.", Headings: []string{"Guide"}},
+			Children: []types.Chunk{{Text: "One.", Headings: []string{"Guide"}}, {Text: "Two.", Position: 5, Headings: []string{"Guide"}}},
+		},
+		{
+			Parent:   types.Chunk{Text: "Three.", Position: 10},
+			Children: []types.Chunk{{Text: "Three.", Position: 10}},
+		},
+	}
+	var mu sync.Mutex
+	var embedded []string
+	ms := &memStore{}
+	vs := store.NewMemoryVectorStore()
+	ec := &EmbedChain{
+		DocName: "guide",
+		EmbedFunc: func(text string) ([]float32, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			embedded = append(embedded, text)
+			return []float32{1, 0}, nil
+		},
+		MetaStore: ms,
+		VectorDB:  vs,
+		BatchSize: 1,
+	}
+	if _, err := ec.RunFamilies(families); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parents are stored before their children and are not embedded.
+	if want := []string{"Guide\n\nOne.", "Guide\n\nTwo.", "Three."}; !reflect.DeepEqual(embedded, want) {
+		t.Errorf("embedded %q, want %q", embedded, want)
+	}
+	type stored struct {
+		Text, ParentID string
+	}
+	var got []stored
+	for _, c := range ms.chunks {
+		got = append(got, stored{c.Text, c.ParentID})
+	}
+	want := []stored{{"One. Two.", ""}, {"One.", "1"}, {"Two.", "1"}, {"Three.", ""}, {"Three.", "4"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored %+v, want %+v", got, want)
+	}
+	ids, err := vs.SearchSimilar([]float32{1, 0}, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("indexed %d vectors, want 3", len(ids))
+	}
+}
+
+type ctxKey struct{}
+
+// ctxChunker is a ContextChunker that records the context value it chunks under.
+type ctxChunker struct{ seen []any }
+
+func (c *ctxChunker) Chunk(text string) ([]types.Chunk, error) {
+	return c.ChunkContext(context.Background(), text)
+}
+func (c *ctxChunker) ChunkContext(ctx context.Context, text string) ([]types.Chunk, error) {
+	c.seen = append(c.seen, ctx.Value(ctxKey{}))
+	return []types.Chunk{{Text: text}}, nil
+}
+func (c *ctxChunker) Name() string { return "ctx-chunker" }
+
+func TestRunContextChunker(t *testing.T) {
+	runContext := func(ctx context.Context, ec *EmbedChain) (string, error) { return ec.RunContext(ctx, "text") }
+	runPagesContext := func(ctx context.Context, ec *EmbedChain) (string, error) {
+		return ec.RunPagesContext(ctx, []string{"one", "two"})
+	}
+	tests := []struct {
+		name   string
+		family bool
+		run    func(context.Context, *EmbedChain) (string, error)
+	}{
+		{"RunContext", false, runContext},
+		{"RunPagesContext", false, runPagesContext},
+		{"RunContext families", true, runContext},
+		{"RunPagesContext families", true, runPagesContext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &ctxChunker{}
+			var c chunker.Chunker = ch
+			if tt.family {
+				c = chunker.NewParentChildChunker(ch, ch)
+			}
+			ec := &EmbedChain{
+				DocName:   "doc",
+				Chunker:   c,
+				EmbedFunc: func(string) ([]float32, error) { return []float32{1, 0}, nil },
+				MetaStore: &memStore{},
+				VectorDB:  store.NewMemoryVectorStore(),
+			}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "run")
+			if _, err := tt.run(ctx, ec); err != nil {
+				t.Fatal(err)
+			}
+			if len(ch.seen) == 0 {
+				t.Fatal("chunker was not called")
+			}
+			for _, v := range ch.seen {
+				if v != "run" {
+					t.Errorf("chunker saw contexts %v, want the caller's", ch.seen)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package chain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+// SaveFile records the file at filePath under docName when ms is a
+// store.FileSaver, with its size, content hash, page count and the metadata
+// rd extracts. Stores that do not record files are left alone. A file whose
+// metadata cannot be extracted is still recorded, and the error returned.
+// The file is hashed as it is read, so large files are never held in memory.
+func SaveFile(ms store.MetadataStore, rd reader.Reader, docName, filePath string, pages int) error {
+	fs, ok := ms.(store.FileSaver)
+	if !ok {
+		return nil
+	}
+	size, hash, err := hashFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to record file info for %s: %w", docName, err)
+	}
+	file := types.FileMeta{
+		Name:     docName,
+		Path:     filePath,
+		AddedAt:  time.Now().UTC().Format(time.RFC3339),
+		FileType: reader.FileType(filePath, rd),
+		Size:     size,
+		Hash:     hash,
+		Pages:    pages,
+	}
+	var metaErr error
+	if mr, ok := rd.(reader.MetadataReader); ok {
+		if file.Meta, metaErr = mr.ExtractMetadata(filePath); metaErr != nil {
+			metaErr = fmt.Errorf("metadata extraction failed for %s: %w", docName, metaErr)
+		}
+	}
+	if _, err := fs.SaveFile(file); err != nil {
+		return err
+	}
+	return metaErr
+}
+
+// hashFile returns the size of the file at path and the hex-encoded SHA-256
+// of its content.
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, "", err
+	}
+	return info.Size(), hex.EncodeToString(h.Sum(nil)), nil
+}
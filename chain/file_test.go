@@ -0,0 +1,59 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/store"
+)
+
+// notesHash is the SHA-256 of "# Notes\n".
+const notesHash = "365d0b84ae63c2afc293dedd2b00bdf0dc8d6ef70c9297d90f9e5682ab0d72ee"
+
+// chunkOnlyStore is a MetadataStore that does not record files.
+type chunkOnlyStore struct{ store.MetadataStore }
+
+func TestSaveFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		wrap  func(*store.SQLiteStore) store.MetadataStore
+		files int
+	}{
+		{"file saver", func(s *store.SQLiteStore) store.MetadataStore { return s }, 1},
+		{"store without files", func(s *store.SQLiteStore) store.MetadataStore { return chunkOnlyStore{s} }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "notes.md")
+			if err := os.WriteFile(path, []byte("# Notes\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			ms := store.NewSQLiteStore()
+			if err := ms.Init(filepath.Join(dir, "test.db")); err != nil {
+				t.Fatal(err)
+			}
+			defer ms.Close()
+
+			if err := SaveFile(tt.wrap(ms), reader.NewMarkdownReader(), "notes", path, 2); err != nil {
+				t.Fatal(err)
+			}
+			files, err := ms.ListFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != tt.files {
+				t.Fatalf("ListFiles() = %+v, want %d files", files, tt.files)
+			}
+			if tt.files == 0 {
+				return
+			}
+			got := files[0]
+			if got.Name != "notes" || got.Path != path || got.FileType != "md" || got.Size != 8 || got.Pages != 2 || got.Hash != notesHash || got.AddedAt == "" {
+				t.Errorf("ListFiles()[0] = %+v", got)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package chain
 
+import "context"
+
 type Chain interface {
   Run(query string, docNameFilter string) (string, error)
 }
+
+// ContextChain is implemented by chains whose model calls can be bound to a
+// context, so that a caller can set a deadline or cancel a run.
+type ContextChain interface {
+	Chain
+	RunContext(ctx context.Context, query string, docNameFilter string) (string, error)
+}
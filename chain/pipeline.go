@@ -0,0 +1,290 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+// entry is a chunk on its way through the ingestion pipeline.
+type entry struct {
+	chunk  types.Chunk
+	family int  // index of the family the chunk belongs to, or -1
+	parent bool // parents are saved but not embedded
+}
+
+// batch is a run of entries that is embedded in one go. vectors holds one
+// vector per entry that is not a parent.
+type batch struct {
+	seq     int
+	entries []entry
+	vectors [][]float32
+	err     error
+}
+
+// index embeds and stores entries. Batches of up to BatchSize chunks are
+// embedded by Workers goroutines, while the calling goroutine saves finished
+// batches in their original order, so chunk IDs follow the document. At most
+// twice Workers batches are in flight at a time. A failed batch stops the
+// remaining work once the batches before it are saved, so the error is that
+// of the first failed batch in the document; cancelling ctx stops it at once.
+func (e *EmbedChain) index(ctx context.Context, entries []entry) error {
+	batches, total := splitBatches(entries, e.batchSize())
+	if len(batches) == 0 {
+		return nil
+	}
+	workers := max(e.Workers, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// A batch takes a token before it is embedded and returns it once it is
+	// written, which keeps a slow batch from letting the others pile up.
+	tokens := make(chan struct{}, 2*workers)
+	jobs := make(chan *batch)
+	results := make(chan *batch, workers)
+
+	go func() {
+		defer close(jobs)
+		for i, entries := range batches {
+			select {
+			case tokens <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- &batch{seq: i, entries: entries}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				b.vectors, b.err = e.embed(ctx, b.entries)
+				select {
+				case results <- b:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]*batch)
+	familyIDs := make(map[int]int64)
+	next, done := 0, 0
+	for {
+		var b *batch
+		select {
+		case b = <-results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if b == nil {
+			// The workers also stop when ctx is cancelled, which can close
+			// results before every batch is written.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if next != len(batches) {
+				return fmt.Errorf("embedding stopped after %d of %d batches", next, len(batches))
+			}
+			return nil
+		}
+		pending[b.seq] = b
+		for {
+			b, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if b.err != nil {
+				return b.err
+			}
+			next++
+			if err := e.write(b, familyIDs); err != nil {
+				return err
+			}
+			<-tokens
+			done += len(b.vectors)
+			if e.Progress != nil {
+				e.Progress(done, total)
+			}
+		}
+	}
+}
+
+// splitBatches cuts entries into runs of up to size chunks, not counting
+// parents, and returns them with the number of chunks to embed.
+func splitBatches(entries []entry, size int) ([][]entry, int) {
+	var batches [][]entry
+	start, n, total := 0, 0, 0
+	for i, en := range entries {
+		if en.parent {
+			continue
+		}
+		n++
+		total++
+		if n == size {
+			batches = append(batches, entries[start:i+1])
+			start, n = i+1, 0
+		}
+	}
+	if start < len(entries) {
+		batches = append(batches, entries[start:])
+	}
+	return batches, total
+}
+
+// embed returns the vectors of the chunks in entries that are not parents.
+func (e *EmbedChain) embed(ctx context.Context, entries []entry) ([][]float32, error) {
+	var texts []string
+	for _, en := range entries {
+		if !en.parent {
+			texts = append(texts, embedText(en.chunk))
+		}
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	var vectors [][]float32
+	var err error
+	switch {
+	case e.EmbedBatchContextFunc != nil:
+		vectors, err = e.EmbedBatchContextFunc(ctx, texts)
+	case e.EmbedBatchFunc != nil:
+		if err = ctx.Err(); err == nil {
+			vectors, err = e.EmbedBatchFunc(texts)
+		}
+	default:
+		vectors = make([][]float32, len(texts))
+		for i, text := range texts {
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			if vectors[i], err = e.EmbedFunc(text); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("embedding error: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedding error: got %d vectors for %d chunks", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// write saves the chunks of an embedded batch and adds their vectors to the
+// vector store. Parents are saved on their own so that the children after
+// them can refer to their ID.
+//
+// Chunks are saved before their vectors are added. When adding the vectors
+// fails, the chunks already saved stay in the metadata store without
+// vectors, so search never returns them; with a VectorBatchAdder no vector
+// of the failed group is added.
+func (e *EmbedChain) write(b *batch, familyIDs map[int]int64) error {
+	var chunks []types.Chunk
+	var vectors [][]float32
+	flush := func() error {
+		if len(chunks) == 0 {
+			return nil
+		}
+		ids, err := e.saveChunks(chunks)
+		if err != nil {
+			return fmt.Errorf("failed to save chunk: %w", err)
+		}
+		if err := e.addVectors(ids, vectors); err != nil {
+			return fmt.Errorf("vector store error: %w", err)
+		}
+		chunks, vectors = chunks[:0], vectors[:0]
+		return nil
+	}
+
+	embedded := 0
+	for _, en := range b.entries {
+		chunk := en.chunk
+		chunk.Meta = chunkMeta(e.Meta, chunk.Meta)
+		if en.parent {
+			if err := flush(); err != nil {
+				return err
+			}
+			id, err := e.MetaStore.SaveChunk(e.DocName, chunk)
+			if err != nil {
+				return fmt.Errorf("failed to save parent chunk: %w", err)
+			}
+			familyIDs[en.family] = id
+			continue
+		}
+		if en.family >= 0 {
+			chunk.ParentID = fmt.Sprintf("%d", familyIDs[en.family])
+		}
+		chunks = append(chunks, chunk)
+		vectors = append(vectors, b.vectors[embedded])
+		embedded++
+	}
+	return flush()
+}
+
+// saveChunks saves chunks in one call when the store supports it.
+func (e *EmbedChain) saveChunks(chunks []types.Chunk) ([]int64, error) {
+	if bs, ok := e.MetaStore.(store.ChunkBatchSaver); ok {
+		return bs.SaveChunks(e.DocName, chunks)
+	}
+	ids := make([]int64, len(chunks))
+	for i, chunk := range chunks {
+		id, err := e.MetaStore.SaveChunk(e.DocName, chunk)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// chunkMeta returns the metadata of a chunk: the chain's metadata overlaid
+// with the chunk's own. Either map is returned as is when the other is empty.
+func chunkMeta(chain, own map[string]string) map[string]string {
+	if len(chain) == 0 {
+		return own
+	}
+	if len(own) == 0 {
+		return chain
+	}
+	meta := make(map[string]string, len(chain)+len(own))
+	for k, v := range chain {
+		meta[k] = v
+	}
+	for k, v := range own {
+		meta[k] = v
+	}
+	return meta
+}
+
+// addVectors adds vectors in one call when the store supports it.
+func (e *EmbedChain) addVectors(ids []int64, vectors [][]float32) error {
+	if ba, ok := e.VectorDB.(store.VectorBatchAdder); ok {
+		return ba.AddVectors(ids, vectors, e.DocName)
+	}
+	for i, id := range ids {
+		if err := e.VectorDB.AddVector(id, vectors[i], e.DocName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
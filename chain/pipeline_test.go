@@ -0,0 +1,217 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+func numberedChunks(n int) []types.Chunk {
+	chunks := make([]types.Chunk, n)
+	for i := range chunks {
+		chunks[i] = types.Chunk{Text: fmt.Sprintf("chunk %d", i), Position: i}
+	}
+	return chunks
+}
+
+func TestSplitBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+		size    int
+		lens    []int
+		total   int
+	}{
+		{"empty", nil, 2, nil, 0},
+		{"even", []entry{{}, {}, {}, {}}, 2, []int{2, 2}, 4},
+		{"remainder", []entry{{}, {}, {}}, 2, []int{2, 1}, 3},
+		{"parents not counted", []entry{{parent: true}, {}, {}, {parent: true}, {}}, 2, []int{3, 2}, 3},
+		{"trailing parent", []entry{{}, {}, {parent: true}}, 2, []int{2, 1}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches, total := splitBatches(tt.entries, tt.size)
+			var lens []int
+			for _, b := range batches {
+				lens = append(lens, len(b))
+			}
+			if !reflect.DeepEqual(lens, tt.lens) || total != tt.total {
+				t.Errorf("got batches %v and total %d, want %v and %d", lens, total, tt.lens, tt.total)
+			}
+		})
+	}
+}
+
+func TestIndexOrderAndProgress(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			chunks := numberedChunks(20)
+			ms := &memStore{}
+			var progress []int
+			ec := &EmbedChain{
+				DocName: "doc",
+				EmbedBatchFunc: func(texts []string) ([][]float32, error) {
+					// Later batches finish first, to check they are still saved in order.
+					time.Sleep(time.Duration(20-len(texts[0])) * time.Millisecond)
+					return make([][]float32, len(texts)), nil
+				},
+				MetaStore: ms,
+				VectorDB:  store.NewMemoryVectorStore(),
+				BatchSize: 3,
+				Workers:   workers,
+				Progress:  func(done, total int) { progress = append(progress, done, total) },
+			}
+			if _, err := ec.RunChunks(chunks); err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range ms.chunks {
+				if c.Text != chunks[i].Text {
+					t.Fatalf("chunk %d is %q, want %q", i, c.Text, chunks[i].Text)
+				}
+			}
+			want := []int{3, 20, 6, 20, 9, 20, 12, 20, 15, 20, 18, 20, 20, 20}
+			if !reflect.DeepEqual(progress, want) {
+				t.Errorf("progress %v, want %v", progress, want)
+			}
+		})
+	}
+}
+
+// batchVectorStore is a VectorStore that only takes vectors in batches and
+// records the size of each.
+type batchVectorStore struct {
+	*store.MemoryVectorStore
+	batches []int
+}
+
+func (s *batchVectorStore) AddVector(int64, []float32, string) error {
+	return errors.New("AddVector called")
+}
+
+func (s *batchVectorStore) AddVectors(ids []int64, vecs [][]float32, docName string) error {
+	s.batches = append(s.batches, len(ids))
+	return s.MemoryVectorStore.AddVectors(ids, vecs, docName)
+}
+
+func TestIndexAddsVectorsInBatches(t *testing.T) {
+	vs := &batchVectorStore{MemoryVectorStore: store.NewMemoryVectorStore()}
+	ec := &EmbedChain{
+		DocName:   "doc",
+		EmbedFunc: func(string) ([]float32, error) { return []float32{1, 0}, nil },
+		MetaStore: &memStore{},
+		VectorDB:  vs,
+		BatchSize: 4,
+	}
+	if _, err := ec.RunChunks(numberedChunks(10)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 4, 2}; !reflect.DeepEqual(vs.batches, want) {
+		t.Errorf("added vectors in batches of %v, want %v", vs.batches, want)
+	}
+}
+
+func TestIndexErrorOrder(t *testing.T) {
+	errBatch := errors.New("batch failed")
+	tests := []struct {
+		name   string
+		failAt []int // batches whose embedding fails
+		stored int
+	}{
+		{"first batch", []int{0}, 0},
+		{"middle batch", []int{3}, 6},
+		{"earliest failure wins", []int{4, 2}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &memStore{}
+			ec := &EmbedChain{
+				DocName: "doc",
+				EmbedBatchFunc: func(texts []string) ([][]float32, error) {
+					var n int
+					fmt.Sscanf(texts[0], "chunk %d", &n)
+					batch := n / 2
+					for i, fail := range tt.failAt {
+						if batch == fail {
+							// Earlier failures in the list come back sooner.
+							time.Sleep(time.Duration(i+1) * 10 * time.Millisecond)
+							return nil, fmt.Errorf("batch %d: %w", batch, errBatch)
+						}
+					}
+					return make([][]float32, len(texts)), nil
+				},
+				MetaStore: ms,
+				VectorDB:  store.NewMemoryVectorStore(),
+				BatchSize: 2,
+				Workers:   4,
+			}
+			_, err := ec.RunChunks(numberedChunks(12))
+			if !errors.Is(err, errBatch) {
+				t.Fatalf("got %v, want %v", err, errBatch)
+			}
+			if want := fmt.Sprintf("batch %d", slices.Min(tt.failAt)); !strings.HasPrefix(err.Error(), "embedding error: "+want+":") {
+				t.Errorf("got %v, want the error of %s", err, want)
+			}
+			// Every batch before the failed one is saved, and none after it.
+			if len(ms.chunks) != tt.stored {
+				t.Errorf("stored %d chunks, want %d", len(ms.chunks), tt.stored)
+			}
+		})
+	}
+}
+
+func TestIndexCancel(t *testing.T) {
+	// Cancelling while batches are in flight must fail the run whichever
+	// goroutine notices first.
+	for i := range 50 {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		ec := &EmbedChain{
+			DocName: "doc",
+			EmbedBatchFunc: func(texts []string) ([][]float32, error) {
+				if calls.Add(1) == 3 {
+					cancel()
+				}
+				return make([][]float32, len(texts)), nil
+			},
+			MetaStore: &memStore{},
+			VectorDB:  store.NewMemoryVectorStore(),
+			BatchSize: 1,
+			Workers:   4,
+		}
+		_, err := ec.RunChunksContext(ctx, numberedChunks(40))
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("run %d: got %v, want context.Canceled", i, err)
+		}
+	}
+}
+
+func TestChunkMeta(t *testing.T) {
+	tests := []struct {
+		name       string
+		chain, own map[string]string
+		want       map[string]string
+	}{
+		{"neither", nil, nil, nil},
+		{"chain only", map[string]string{"path": "a.csv"}, nil, map[string]string{"path": "a.csv"}},
+		{"own only", nil, map[string]string{"records": "1-2"}, map[string]string{"records": "1-2"}},
+		{"merged", map[string]string{"path": "a.csv", "kind": "file"}, map[string]string{"records": "1-2", "kind": "rows"},
+			map[string]string{"path": "a.csv", "records": "1-2", "kind": "rows"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkMeta(tt.chain, tt.own); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
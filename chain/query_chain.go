@@ -1,10 +1,12 @@
 package chain
 
 import (
-	//"strings"
+	"context"
+	"strings"
   "fmt"
 	//"github.com/Ashank007/docai/embedder"
 	"github.com/Ashank007/docai/retriever"
+	"github.com/Ashank007/docai/types"
 	//"github.com/Ashank007/docai/generator"
 )
 
@@ -12,21 +14,55 @@ type QueryChain struct {
 	EmbedFunc func(string) ([]float32, error)
 	Retriever retriever.Retriever
 	Generator func(string, []string) (string, error)
+
+	// GeneratorContext, when set, answers in RunContext instead of
+	// Generator, e.g. generator.OllamaGenerator.GenerateContext.
+	GeneratorContext func(context.Context, string, []string) (string, error)
 }
 
 func (q *QueryChain) Run(query string, docNameFilter string) (string, error) { // <--- CORRECTED LINE HERE
-	// Retriever.Retrieve needs to be updated to accept docNameFilter
-	// This call is now correct as retriever.Retriever interface is fixed.
-	chunks, err := q.Retriever.Retrieve(query, 4, docNameFilter)
+	return q.RunContext(context.Background(), query, docNameFilter)
+}
+
+// RunContext is Run with retrieval and generation bound to ctx.
+func (q *QueryChain) RunContext(ctx context.Context, query string, docNameFilter string) (string, error) {
+	var chunks []types.RetrievedChunk
+	var err error
+	if cr, ok := q.Retriever.(retriever.ContextRetriever); ok {
+		chunks, err = cr.RetrieveContext(ctx, query, 4, docNameFilter)
+	} else if err = ctx.Err(); err == nil {
+		chunks, err = q.Retriever.Retrieve(query, 4, docNameFilter)
+	}
 	if err != nil {
 		return "", fmt.Errorf("retrieval failed: %w", err) // Use fmt.Errorf for better error wrapping
 	}
 
 	var contexts []string
 	for _, c := range chunks {
-		contexts = append(contexts, c.Chunk.Text)
+		contexts = append(contexts, contextText(c))
 	}
 
+	if q.GeneratorContext != nil {
+		return q.GeneratorContext(ctx, query, contexts)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	return q.Generator(query, contexts)
 }
 
+// contextText labels a retrieved chunk with its source, page and heading
+// path, when known, so the generator can say where an answer came from.
+func contextText(c types.RetrievedChunk) string {
+	var label []string
+	if c.Chunk.Page > 0 {
+		label = append(label, fmt.Sprintf("%s, page %d", c.Chunk.Source, c.Chunk.Page))
+	}
+	if len(c.Chunk.Headings) > 0 {
+		label = append(label, strings.Join(c.Chunk.Headings, " > "))
+	}
+	if len(label) == 0 {
+		return c.Chunk.Text
+	}
+	return fmt.Sprintf("[%s] %s", strings.Join(label, "; "), c.Chunk.Text)
+}
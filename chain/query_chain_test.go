@@ -0,0 +1,55 @@
+package chain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// fixedRetriever returns the same chunks for every query.
+type fixedRetriever []types.RetrievedChunk
+
+func (r fixedRetriever) Retrieve(string, int, string) ([]types.RetrievedChunk, error) {
+	return r, nil
+}
+
+func TestContextText(t *testing.T) {
+	tests := []struct {
+		name  string
+		chunk types.Chunk
+		want  string
+	}{
+		{"unlabelled", types.Chunk{Text: "Run it.", Source: "notes"}, "Run it."},
+		{"page", types.Chunk{Text: "Run it.", Source: "guide.pdf", Page: 3}, "[guide.pdf, page 3] Run it."},
+		{"headings", types.Chunk{Text: "Run it.", Source: "guide.md", Headings: []string{"Guide", "Install"}}, "[Guide > Install] Run it."},
+		{"page and headings", types.Chunk{Text: "Run it.", Source: "guide.docx", Page: 2, Headings: []string{"Install"}}, "[guide.docx, page 2; Install] Run it."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contextText(types.RetrievedChunk{Chunk: tt.chunk}); got != tt.want {
+				t.Errorf("contextText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryChainContexts(t *testing.T) {
+	var got []string
+	q := &QueryChain{
+		Retriever: fixedRetriever{
+			{Chunk: types.Chunk{Text: "Run it.", Headings: []string{"Guide", "Install"}}},
+			{Chunk: types.Chunk{Text: "Plain."}},
+		},
+		Generator: func(query string, contexts []string) (string, error) {
+			got = contexts
+			return "answer", nil
+		},
+	}
+	if _, err := q.Run("how do I install?", ""); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[Guide > Install] Run it.", "Plain."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("generator got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,401 @@
+package chunker
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// CodeChunker splits source code on function and type boundaries.
+//
+// Go source is parsed with go/parser and split into one chunk per top-level
+// declaration. Other languages use brace depth or, for indentation-based
+// languages such as Python, indentation to find top-level blocks; blocks
+// longer than MaxLines are split at their members (e.g. the methods of a
+// class) and then, if still too long, into runs of MaxLines lines.
+//
+// Every chunk records its symbol in Meta["symbol"], the kind of declaration
+// in Meta["kind"] and its 1-based line range in Meta["lines"] (e.g. "12-40").
+// Position holds the character (rune) offset of the chunk in the text.
+type CodeChunker struct {
+	// Language selects the splitting strategy, e.g. "go" or "python". When
+	// empty, Go is tried first and other text is split by braces if it has
+	// any and by indentation otherwise.
+	Language string
+	MaxLines int
+}
+
+// NewCodeChunker creates a CodeChunker. A non-positive maxLines falls back to 80.
+func NewCodeChunker(maxLines int) *CodeChunker {
+	if maxLines <= 0 {
+		maxLines = 80
+	}
+	return &CodeChunker{MaxLines: maxLines}
+}
+
+// indentLanguages are split by indentation rather than by braces.
+var indentLanguages = map[string]bool{"python": true, "yaml": true, "haskell": true, "nim": true, "fsharp": true}
+
+// codeBlock is an inclusive, 0-based line range holding one declaration.
+type codeBlock struct {
+	start, end   int
+	symbol, kind string
+}
+
+func (cc *CodeChunker) Chunk(text string) ([]types.Chunk, error) {
+	// Offsets are taken in the text as given, before "\r\n" is replaced;
+	// both have the same lines.
+	offsets := runeOffsets{text: text}
+	lineStarts := []int{0}
+	for i, r := range text {
+		if r == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	var blocks []codeBlock
+	lang := strings.ToLower(cc.Language)
+	if lang == "go" || lang == "" {
+		// Go that does not parse, such as a fragment, falls back to braces.
+		if b, err := goBlocks(text, len(lines)); err == nil {
+			blocks = b
+		}
+	}
+	if blocks == nil {
+		s := &codeSplitter{lines: lines, maxLines: cc.MaxLines}
+		if indentLanguages[lang] || (lang == "" && !strings.Contains(text, "{")) {
+			s.indentLevels()
+		} else {
+			s.braceLevels()
+		}
+		blocks = s.split(0, len(lines)-1, "", 0)
+	}
+
+	var chunks []types.Chunk
+	for _, b := range blocks {
+		for b.start <= b.end && strings.TrimSpace(lines[b.start]) == "" {
+			b.start++
+		}
+		for b.end >= b.start && strings.TrimSpace(lines[b.end]) == "" {
+			b.end--
+		}
+		if !strings.ContainsFunc(strings.Join(lines[b.start:b.end+1], ""), isWordRune) {
+			continue // a lone closing brace
+		}
+		for start := b.start; start <= b.end; start += cc.MaxLines {
+			end := min(start+cc.MaxLines-1, b.end)
+			meta := map[string]string{"lines": fmt.Sprintf("%d-%d", start+1, end+1)}
+			if b.symbol != "" {
+				meta["symbol"] = b.symbol
+			}
+			if b.kind != "" {
+				meta["kind"] = b.kind
+			}
+			chunks = append(chunks, types.Chunk{
+				Text:     strings.Join(lines[start:end+1], "\n"),
+				Position: offsets.at(lineStarts[start]),
+				Meta:     meta,
+			})
+		}
+	}
+	return chunks, nil
+}
+
+func (cc *CodeChunker) Name() string {
+	return "code-chunker"
+}
+
+// goBlocks parses Go source and returns the package clause with its imports
+// followed by one block per top-level declaration. Comments between
+// declarations belong to the declaration that follows them.
+func goBlocks(src string, lineCount int) ([]codeBlock, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	line := func(p token.Pos) int { return fset.Position(p).Line - 1 }
+
+	header := codeBlock{start: 0, end: line(file.Name.End()), symbol: file.Name.Name, kind: "package"}
+	decls := file.Decls
+	for len(decls) > 0 {
+		gd, ok := decls[0].(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			break
+		}
+		header.end = line(gd.End())
+		decls = decls[1:]
+	}
+	blocks := []codeBlock{header}
+
+	for _, decl := range decls {
+		b := codeBlock{start: blocks[len(blocks)-1].end + 1, end: line(decl.End())}
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			b.symbol, b.kind = d.Name.Name, "func"
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				b.symbol, b.kind = goReceiverName(d.Recv.List[0].Type)+"."+d.Name.Name, "method"
+			}
+		case *ast.GenDecl:
+			var names []string
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						names = append(names, n.Name)
+					}
+				}
+			}
+			b.symbol, b.kind = strings.Join(names, ", "), d.Tok.String()
+		}
+		blocks = append(blocks, b)
+	}
+	blocks[len(blocks)-1].end = lineCount - 1
+	return blocks, nil
+}
+
+// goReceiverName returns the type name of a method receiver such as *List[T].
+func goReceiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexListExpr:
+		return goReceiverName(t.X)
+	case *ast.ParenExpr:
+		return goReceiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// codeSplitter finds declaration blocks in languages without a parser.
+// unitEnd reports where the block that starts on a line ends.
+type codeSplitter struct {
+	lines    []string
+	maxLines int
+	unitEnd  func(i, to int) int
+}
+
+// braceLevels ends a block once every bracket opened on or after its first
+// line is closed. Brackets inside strings and comments are ignored.
+func (s *codeSplitter) braceLevels() {
+	depthBefore := make([]int, len(s.lines))
+	depthAfter := make([]int, len(s.lines))
+	depth := 0
+	inBlockComment := false
+	var multiline byte // open ` string
+	for i, line := range s.lines {
+		depthBefore[i] = depth
+		var quote byte
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case inBlockComment:
+				if c == '*' && j+1 < len(line) && line[j+1] == '/' {
+					inBlockComment = false
+					j++
+				}
+			case multiline != 0:
+				if c == multiline {
+					multiline = 0
+				}
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '/' && j+1 < len(line) && line[j+1] == '/':
+				j = len(line)
+			case c == '/' && j+1 < len(line) && line[j+1] == '*':
+				inBlockComment = true
+				j++
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '`':
+				multiline = c
+			case c == '{' || c == '(' || c == '[':
+				depth++
+			case c == '}' || c == ')' || c == ']':
+				if depth > 0 {
+					depth--
+				}
+			}
+		}
+		depthAfter[i] = depth
+	}
+
+	s.unitEnd = func(i, to int) int {
+		j := i
+		for j < to && depthAfter[j] > depthBefore[i] {
+			j++
+		}
+		// Allman style puts the opening brace of a declaration on its own line.
+		if k := s.nextNonBlank(j+1, to); k >= 0 && strings.HasPrefix(strings.TrimSpace(s.lines[k]), "{") && !isCommentLine(s.lines[j]) {
+			return s.unitEnd(k, to)
+		}
+		return j
+	}
+}
+
+// indentLevels ends a block before the next line indented no deeper than its first line.
+func (s *codeSplitter) indentLevels() {
+	indent := func(line string) int {
+		n := 0
+		for _, c := range line {
+			switch c {
+			case ' ':
+				n++
+			case '\t':
+				n += 4
+			default:
+				return n
+			}
+		}
+		return n
+	}
+	s.unitEnd = func(i, to int) int {
+		base := indent(s.lines[i])
+		end := i
+		for j := i + 1; j <= to; j++ {
+			if strings.TrimSpace(s.lines[j]) == "" {
+				continue
+			}
+			if indent(s.lines[j]) <= base {
+				break
+			}
+			end = j
+		}
+		return end
+	}
+}
+
+func (s *codeSplitter) nextNonBlank(i, to int) int {
+	for ; i <= to; i++ {
+		if strings.TrimSpace(s.lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// split returns the blocks of lines [from, to]. Lines outside any named
+// declaration are grouped together and labelled with parent. Comment and
+// annotation lines directly above a declaration are kept with it.
+func (s *codeSplitter) split(from, to int, parent string, depth int) []codeBlock {
+	var blocks, loose []codeBlock
+	flushLoose := func() {
+		for len(loose) > 0 {
+			b := codeBlock{start: loose[0].start, end: loose[0].end, symbol: parent}
+			n := 1
+			for n < len(loose) && loose[n].end-b.start < s.maxLines {
+				b.end = loose[n].end
+				n++
+			}
+			blocks = append(blocks, b)
+			loose = loose[n:]
+		}
+	}
+
+	for i := from; i <= to; {
+		if strings.TrimSpace(s.lines[i]) == "" {
+			i++
+			continue
+		}
+		end := s.unitEnd(i, to)
+		name, kind := codeSymbol(s.lines[i])
+		if name == "" || isCommentLine(s.lines[i]) {
+			loose = append(loose, codeBlock{start: i, end: end})
+			i = end + 1
+			continue
+		}
+		if parent != "" {
+			name = parent + "." + name
+		}
+
+		start := i
+		for len(loose) > 0 {
+			last := loose[len(loose)-1]
+			if last.end+1 != start || !isCommentLine(s.lines[last.start]) {
+				break
+			}
+			start = last.start
+			loose = loose[:len(loose)-1]
+		}
+		flushLoose()
+
+		if end-start+1 > s.maxLines && end > i && depth < 3 {
+			// Split an oversized class or module at its members, keeping the
+			// declaration line with whatever precedes the first member.
+			header := codeBlock{start: start, end: i, symbol: name, kind: kind}
+			inner := s.split(i+1, end, name, depth+1)
+			if len(inner) > 0 && inner[0].symbol == name && inner[0].end-header.start < s.maxLines {
+				header.end = inner[0].end
+				inner = inner[1:]
+			}
+			blocks = append(blocks, header)
+			blocks = append(blocks, inner...)
+		} else {
+			blocks = append(blocks, codeBlock{start: start, end: end, symbol: name, kind: kind})
+		}
+		i = end + 1
+	}
+	flushLoose()
+	return blocks
+}
+
+var (
+	codeDeclPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(func|function|def|fn|sub|proc|fun)\s+(?:\([^)]*\)\s*)?([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`\b(class|struct|interface|enum|trait|impl|type|module|namespace|object|record|union|protocol|extension)\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`\b(const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s*)?(?:function\b|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>)`),
+	}
+	// codeCallLike matches C-family function definitions such as "int main(void) {".
+	codeCallLike = regexp.MustCompile(`^\s*(?:[\w:<>,*&\[\]~]+\s+)+\**([A-Za-z_~][\w:~]*)\s*\(`)
+	codeKeywords = map[string]bool{"if": true, "for": true, "while": true, "switch": true, "return": true, "catch": true, "else": true, "new": true, "sizeof": true}
+)
+
+// codeSymbol returns the name and kind of the declaration that starts on line, if any.
+func codeSymbol(line string) (string, string) {
+	for _, re := range codeDeclPatterns {
+		if m := re.FindStringSubmatch(line); m != nil {
+			kind := m[1]
+			if kind == "const" || kind == "let" || kind == "var" {
+				kind = "function"
+			}
+			return m[2], kind
+		}
+	}
+	if m := codeCallLike.FindStringSubmatch(line); m != nil && !codeKeywords[m[1]] && !strings.HasSuffix(strings.TrimSpace(line), ";") {
+		return m[1], "function"
+	}
+	return "", ""
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isCommentLine reports whether line is a comment, doc comment or annotation.
+func isCommentLine(line string) bool {
+	t := strings.TrimSpace(line)
+	for _, prefix := range []string{"//", "/*", "*", "#", "@", "--", `"""`, "'''"} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
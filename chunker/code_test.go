@@ -0,0 +1,199 @@
+package chunker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// codeChunk is the symbol, kind and line range expected of one chunk.
+type codeChunk struct {
+	symbol, kind, lines string
+}
+
+func TestCodeChunker(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		maxLines int
+		src      string
+		want     []codeChunk
+	}{
+		{
+			name: "go declarations",
+			src: `package list
+
+import "fmt"
+
+// List is a linked list.
+type List[T any] struct {
+	head *node[T]
+}
+
+// Push adds v to the front.
+func (l *List[T]) Push(v T) {
+	l.head = &node[T]{v, l.head}
+}
+
+const a, b = 1, 2
+
+func Print() { fmt.Println(a) }
+`,
+			want: []codeChunk{
+				{"list", "package", "1-3"},
+				{"List", "type", "5-8"},
+				{"List.Push", "method", "10-13"},
+				{"a, b", "const", "15-15"},
+				{"Print", "func", "17-17"},
+			},
+		},
+		{
+			name:     "javascript braces",
+			language: "javascript",
+			src: `import x from "y";
+
+/** Adds numbers. */
+function add(a, b) {
+  return "}" + a + b;
+}
+
+const mul = (a, b) => {
+  return a * b;
+};
+
+class Calc {
+  run() {}
+}
+`,
+			want: []codeChunk{
+				{"", "", "1-1"},
+				{"add", "function", "3-6"},
+				{"mul", "function", "8-10"},
+				{"Calc", "class", "12-14"},
+			},
+		},
+		{
+			name:     "c allman",
+			language: "c",
+			src: `int main(void)
+{
+    return 0;
+}
+`,
+			want: []codeChunk{{"main", "function", "1-4"}},
+		},
+		{
+			name:     "python indentation",
+			language: "python",
+			src: `import os
+
+@decorator
+def walk(path):
+    return os.walk(path)
+
+class Tree:
+    def size(self):
+        return 0
+`,
+			want: []codeChunk{
+				{"", "", "1-1"},
+				{"walk", "def", "3-5"},
+				{"Tree", "class", "7-9"},
+			},
+		},
+		{
+			name:     "oversized class split at members",
+			language: "python",
+			maxLines: 3,
+			src: `class Tree:
+    def size(self):
+        return 0
+
+    def depth(self):
+        return 1
+`,
+			want: []codeChunk{
+				{"Tree", "class", "1-1"},
+				{"Tree.size", "def", "2-3"},
+				{"Tree.depth", "def", "5-6"},
+			},
+		},
+		{
+			name:     "long block split by lines",
+			language: "go",
+			maxLines: 2,
+			src:      "package p\n\nfunc F() {\n\t_ = 1\n\t_ = 2\n}\n",
+			want: []codeChunk{
+				{"p", "package", "1-1"},
+				{"F", "func", "3-4"},
+				{"F", "func", "5-6"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := NewCodeChunker(tt.maxLines)
+			cc.Language = tt.language
+			chunks, err := cc.Chunk(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []codeChunk
+			for _, c := range chunks {
+				got = append(got, codeChunk{c.Meta["symbol"], c.Meta["kind"], c.Meta["lines"]})
+				if !strings.HasPrefix(string([]rune(tt.src)[c.Position:]), c.Text) {
+					t.Errorf("chunk %q position = %d, which does not start the chunk", c.Meta["symbol"], c.Position)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeSymbol(t *testing.T) {
+	tests := []struct {
+		line, name, kind string
+	}{
+		{"fn parse(input: &str) -> Result<()> {", "parse", "fn"},
+		{"export default class Widget extends Base {", "Widget", "class"},
+		{"static int count_items(list_t *l) {", "count_items", "function"},
+		{"    if (ready) {", "", ""},
+		{"int total(int a, int b);", "", ""},
+		{"let handler = async (req) => {", "handler", "function"},
+	}
+	for _, tt := range tests {
+		name, kind := codeSymbol(tt.line)
+		if name != tt.name || kind != tt.kind {
+			t.Errorf("codeSymbol(%q) = %q, %q, want %q, %q", tt.line, name, kind, tt.name, tt.kind)
+		}
+	}
+}
+
+func TestCodeChunkerPositions(t *testing.T) {
+	// Positions count characters of the text as given, "\r\n" included.
+	tests := []struct {
+		name      string
+		src       string
+		positions []int
+	}{
+		{"LF", "package p\n\n// Grüße\nfunc F() {}\n", []int{0, 11}},
+		{"CRLF", "package p\r\n\r\n// Grüße\r\nfunc F() {}\r\n", []int{0, 13}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := NewCodeChunker(80).Chunk(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, c := range chunks {
+				got = append(got, c.Position)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.positions) {
+				t.Errorf("positions %v, want %v", got, tt.positions)
+			}
+		})
+	}
+}
@@ -1,8 +1,31 @@
 package chunker
 
-import "github.com/Ashank007/docai/types"
+import (
+	"context"
+
+	"github.com/Ashank007/docai/types"
+)
 
 type Chunker interface {
 	Chunk(text string) ([]types.Chunk, error)
 	Name() string
 }
+
+// ContextChunker is implemented by chunkers that call out while chunking,
+// e.g. to embed sentences, and can bind those calls to a context.
+type ContextChunker interface {
+	Chunker
+	ChunkContext(ctx context.Context, text string) ([]types.Chunk, error)
+}
+
+// ChunkContext chunks text with c, bound to ctx when c is a ContextChunker.
+// Other chunkers are not called once ctx is done.
+func ChunkContext(ctx context.Context, c Chunker, text string) ([]types.Chunk, error) {
+	if cc, ok := c.(ContextChunker); ok {
+		return cc.ChunkContext(ctx, text)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Chunk(text)
+}
@@ -0,0 +1,29 @@
+package chunker
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChunkContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		chunks  int
+		wantErr error
+	}{
+		{"live context", context.Background(), 2, nil},
+		{"cancelled context", cancelled, 0, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := ChunkContext(tt.ctx, NewSentenceChunker(1), "One. Two.")
+			if !errors.Is(err, tt.wantErr) || len(chunks) != tt.chunks {
+				t.Errorf("got %d chunks and %v, want %d and %v", len(chunks), err, tt.chunks, tt.wantErr)
+			}
+		})
+	}
+}
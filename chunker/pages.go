@@ -0,0 +1,30 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// ChunkPages chunks each page on its own so that no chunk spans a page
+// boundary, and stamps every chunk with its 1-based page number.
+func ChunkPages(c Chunker, pages []string) ([]types.Chunk, error) {
+	return ChunkPagesContext(context.Background(), c, pages)
+}
+
+// ChunkPagesContext is ChunkPages with every page chunked by ChunkContext.
+func ChunkPagesContext(ctx context.Context, c Chunker, pages []string) ([]types.Chunk, error) {
+	var chunks []types.Chunk
+	for i, page := range pages {
+		pageChunks, err := ChunkContext(ctx, c, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chunk page %d: %w", i+1, err)
+		}
+		for _, chunk := range pageChunks {
+			chunk.Page = i + 1
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, nil
+}
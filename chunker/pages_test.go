@@ -0,0 +1,27 @@
+package chunker
+
+import "testing"
+
+func TestChunkPages(t *testing.T) {
+	pages := []string{"One. Two.", "", "Three. Four. Five."}
+	chunks, err := ChunkPages(NewSentenceChunker(2), pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		text string
+		page int
+	}{
+		{"One. Two.", 1},
+		{"Three. Four.", 3},
+		{"Five.", 3},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(chunks), len(want), chunks)
+	}
+	for i, w := range want {
+		if chunks[i].Text != w.text || chunks[i].Page != w.page {
+			t.Errorf("chunk %d = %q on page %d, want %q on page %d", i, chunks[i].Text, chunks[i].Page, w.text, w.page)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// Family is a parent chunk together with the smaller child chunks cut from it.
+type Family struct {
+	Parent   types.Chunk
+	Children []types.Chunk
+}
+
+// FamilyChunker is implemented by chunkers that emit two levels of chunks:
+// small children that are embedded for precise matching, and the larger
+// parents they belong to, which are handed to the generator instead.
+type FamilyChunker interface {
+	Chunker
+	ChunkFamilies(text string) ([]Family, error)
+}
+
+// ContextFamilyChunker is a FamilyChunker that can bind the chunking of
+// parents and children to a context.
+type ContextFamilyChunker interface {
+	FamilyChunker
+	ChunkFamiliesContext(ctx context.Context, text string) ([]Family, error)
+}
+
+// ChunkFamiliesContext chunks text into families with fc, bound to ctx when
+// fc is a ContextFamilyChunker. Other chunkers are not called once ctx is done.
+func ChunkFamiliesContext(ctx context.Context, fc FamilyChunker, text string) ([]Family, error) {
+	if cc, ok := fc.(ContextFamilyChunker); ok {
+		return cc.ChunkFamiliesContext(ctx, text)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fc.ChunkFamilies(text)
+}
+
+// ParentChildChunker splits text into parent chunks with Parent, then splits
+// every parent into child chunks with Child. Child positions are offsets into
+// the source text, like those of their parents.
+type ParentChildChunker struct {
+	Parent Chunker
+	Child  Chunker
+}
+
+// NewParentChildChunker creates a ParentChildChunker. The parent chunker
+// should produce chunks several times larger than the child chunker.
+func NewParentChildChunker(parent, child Chunker) *ParentChildChunker {
+	return &ParentChildChunker{Parent: parent, Child: child}
+}
+
+func (pc *ParentChildChunker) ChunkFamilies(text string) ([]Family, error) {
+	return pc.ChunkFamiliesContext(context.Background(), text)
+}
+
+// ChunkFamiliesContext is ChunkFamilies with the parent and child chunkers
+// bound to ctx. It stops between parents once ctx is done.
+func (pc *ParentChildChunker) ChunkFamiliesContext(ctx context.Context, text string) ([]Family, error) {
+	parents, err := ChunkContext(ctx, pc.Parent, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to chunk parents: %w", err)
+	}
+	families := make([]Family, 0, len(parents))
+	for i, parent := range parents {
+		children, err := ChunkContext(ctx, pc.Child, parent.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chunk parent %d: %w", i+1, err)
+		}
+		for j := range children {
+			children[j].Position += parent.Position
+			children[j].Headings = parent.Headings
+		}
+		families = append(families, Family{Parent: parent, Children: children})
+	}
+	return families, nil
+}
+
+// Chunk returns the child chunks of every family, without their parents.
+func (pc *ParentChildChunker) Chunk(text string) ([]types.Chunk, error) {
+	return pc.ChunkContext(context.Background(), text)
+}
+
+// ChunkContext is Chunk bound to ctx.
+func (pc *ParentChildChunker) ChunkContext(ctx context.Context, text string) ([]types.Chunk, error) {
+	families, err := pc.ChunkFamiliesContext(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []types.Chunk
+	for _, family := range families {
+		chunks = append(chunks, family.Children...)
+	}
+	return chunks, nil
+}
+
+func (pc *ParentChildChunker) Name() string {
+	return "parent-child-chunker"
+}
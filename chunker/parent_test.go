@@ -0,0 +1,80 @@
+package chunker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// fixedChunker returns the same chunks for any text.
+type fixedChunker []types.Chunk
+
+func (f fixedChunker) Chunk(string) ([]types.Chunk, error) {
+	return append([]types.Chunk(nil), f...), nil
+}
+func (f fixedChunker) Name() string { return "fixed-chunker" }
+
+func TestParentChildChunkerChunkFamilies(t *testing.T) {
+	type child struct {
+		Text     string
+		Position int
+		Headings []string
+	}
+	tests := []struct {
+		name     string
+		parents  []types.Chunk
+		children [][]child
+	}{
+		{
+			name:     "offsets follow the parent",
+			parents:  []types.Chunk{{Text: "One. Two.", Position: 0}, {Text: "Three. Four.", Position: 10}},
+			children: [][]child{{{"One.", 0, nil}, {"Two.", 5, nil}}, {{"Three.", 10, nil}, {"Four.", 17, nil}}},
+		},
+		{
+			name:     "offsets count characters",
+			parents:  []types.Chunk{{Text: "Café à la. Two.", Position: 3}},
+			children: [][]child{{{"Café à la.", 3, nil}, {"Two.", 14, nil}}},
+		},
+		{
+			name:     "headings are inherited",
+			parents:  []types.Chunk{{Text: "Run it. Done.", Position: 40, Headings: []string{"Guide", "Install"}}},
+			children: [][]child{{{"Run it.", 40, []string{"Guide", "Install"}}, {"Done.", 48, []string{"Guide", "Install"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := NewParentChildChunker(fixedChunker(tt.parents), NewSentenceChunker(1))
+			families, err := pc.ChunkFamilies("ignored")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(families) != len(tt.parents) {
+				t.Fatalf("got %d families, want %d", len(families), len(tt.parents))
+			}
+			var all []types.Chunk
+			for i, f := range families {
+				if !reflect.DeepEqual(f.Parent, tt.parents[i]) {
+					t.Errorf("parent %d = %+v, want %+v", i, f.Parent, tt.parents[i])
+				}
+				var got []child
+				for _, c := range f.Children {
+					got = append(got, child{c.Text, c.Position, c.Headings})
+				}
+				if !reflect.DeepEqual(got, tt.children[i]) {
+					t.Errorf("children of parent %d = %+v, want %+v", i, got, tt.children[i])
+				}
+				all = append(all, f.Children...)
+			}
+
+			// Chunk returns the children alone.
+			chunks, err := pc.Chunk("ignored")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(chunks, all) {
+				t.Errorf("Chunk() = %+v, want %+v", chunks, all)
+			}
+		})
+	}
+}
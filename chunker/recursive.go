@@ -0,0 +1,293 @@
+package chunker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Ashank007/docai/tokenizer"
+	"github.com/Ashank007/docai/types"
+)
+
+// SizeUnit selects how a chunker measures the size of a chunk.
+type SizeUnit int
+
+const (
+	Words      SizeUnit = iota // whitespace-separated words
+	Characters                 // Unicode code points
+	Tokens                     // model tokens, counted by a tokenizer.Tokenizer
+)
+
+// DefaultSeparators is the separator hierarchy of RecursiveChunker:
+// paragraphs, lines, sentences, then words.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", "! ", "? ", " "}
+
+// RecursiveChunker splits text on the first separator in Separators that
+// yields pieces no larger than ChunkSize, splitting oversized pieces again on
+// the next separator, and then packs neighbouring pieces back together up to
+// ChunkSize. Each chunk starts with up to Overlap words, characters or tokens
+// from the end of the previous one, so that a fact that straddles a boundary is
+// whole in at least one chunk.
+//
+// Position holds the character (rune) offset of the chunk's first character
+// in the source text, so []rune(text)[chunk.Position:] starts with chunk.Text.
+type RecursiveChunker struct {
+	ChunkSize  int
+	Overlap    int
+	Unit       SizeUnit
+	Separators []string
+	Tokenizer  tokenizer.Tokenizer // counts Tokens; nil uses tokenizer.ApproxTokenizer
+}
+
+// NewRecursiveChunker creates a RecursiveChunker with the default separators.
+// A non-positive chunkSize falls back to 200, and the overlap is capped at
+// half the chunk size.
+func NewRecursiveChunker(chunkSize, overlap int, unit SizeUnit) *RecursiveChunker {
+	if chunkSize <= 0 {
+		chunkSize = 200
+	}
+	overlap = max(0, min(overlap, chunkSize/2))
+	return &RecursiveChunker{
+		ChunkSize:  chunkSize,
+		Overlap:    overlap,
+		Unit:       unit,
+		Separators: DefaultSeparators,
+	}
+}
+
+// NewTokenChunker creates a RecursiveChunker that sizes chunks in tokens
+// counted by tok, so that every chunk fits the context of the model that
+// embeds it. A nil tok falls back to tokenizer.NewApproxTokenizer.
+func NewTokenChunker(tok tokenizer.Tokenizer, maxTokens, overlap int) *RecursiveChunker {
+	if tok == nil {
+		tok = tokenizer.NewApproxTokenizer()
+	}
+	rc := NewRecursiveChunker(maxTokens, overlap, Tokens)
+	rc.Tokenizer = tok
+	return rc
+}
+
+// span is a byte range [start, end) of the source text.
+type span struct {
+	start, end int
+}
+
+func (rc *RecursiveChunker) Chunk(text string) ([]types.Chunk, error) {
+	pieces := rc.split(text, span{0, len(text)}, 0)
+
+	var chunks []types.Chunk
+	offsets := runeOffsets{text: text}
+	emit := func(s span) {
+		raw := text[s.start:s.end]
+		trimmed := strings.TrimLeftFunc(raw, unicode.IsSpace)
+		start := s.start + len(raw) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if trimmed != "" {
+			chunks = append(chunks, types.Chunk{
+				Text:     trimmed,
+				Position: offsets.at(start),
+			})
+		}
+	}
+
+	current := span{-1, -1}
+	for _, p := range pieces {
+		if current.start >= 0 && rc.size(text[current.start:p.end]) > rc.ChunkSize {
+			emit(current)
+			current.start = rc.overlapStart(text, current, p.end)
+		}
+		if current.start < 0 {
+			current.start = p.start
+		}
+		current.end = p.end
+	}
+	if current.start >= 0 {
+		emit(current)
+	}
+	return chunks, nil
+}
+
+func (rc *RecursiveChunker) Name() string {
+	if rc.Unit == Tokens {
+		return "token-chunker"
+	}
+	return "recursive-chunker"
+}
+
+// overlapStart returns where the chunk after prev starts: at the earliest
+// word of prev that keeps the overlap within Overlap and leaves room for the
+// text up to next, or at prev.end when no overlap fits.
+func (rc *RecursiveChunker) overlapStart(text string, prev span, next int) int {
+	if rc.Overlap <= 0 {
+		return prev.end
+	}
+	for _, start := range wordStarts(text, prev) {
+		if start == prev.start {
+			continue
+		}
+		if rc.size(text[start:prev.end]) <= rc.Overlap && rc.size(text[start:next]) <= rc.ChunkSize {
+			return start
+		}
+	}
+	return prev.end
+}
+
+// split returns the pieces of s, in order and covering all of it, each no
+// larger than ChunkSize. Separators stay at the end of the piece before them.
+func (rc *RecursiveChunker) split(text string, s span, level int) []span {
+	if rc.size(text[s.start:s.end]) <= rc.ChunkSize {
+		return []span{s}
+	}
+	if level >= len(rc.Separators) {
+		return rc.hardSplit(text, s)
+	}
+
+	sep := rc.Separators[level]
+	var parts []span
+	start := s.start
+	for start < s.end {
+		i := strings.Index(text[start:s.end], sep)
+		if i < 0 {
+			break
+		}
+		end := start + i + len(sep)
+		parts = append(parts, span{start, end})
+		start = end
+	}
+	if start < s.end {
+		parts = append(parts, span{start, s.end})
+	}
+
+	var pieces []span
+	for _, part := range parts {
+		pieces = append(pieces, rc.split(text, part, level+1)...)
+	}
+	return pieces
+}
+
+// hardSplit cuts a span that no separator could break into pieces of
+// ChunkSize words, characters or tokens.
+func (rc *RecursiveChunker) hardSplit(text string, s span) []span {
+	var cuts []int
+	switch rc.Unit {
+	case Tokens:
+		// Cut before the character that takes the piece over the limit.
+		for start := s.start; ; {
+			end := rc.fitTokens(text, start, s.end)
+			if end >= s.end {
+				break
+			}
+			cuts = append(cuts, end)
+			start = end
+		}
+	case Characters:
+		n := 0
+		for i := range text[s.start:s.end] {
+			if n > 0 && n%rc.ChunkSize == 0 {
+				cuts = append(cuts, s.start+i)
+			}
+			n++
+		}
+	default:
+		for i, start := range wordStarts(text, s) {
+			if i > 0 && i%rc.ChunkSize == 0 {
+				cuts = append(cuts, start)
+			}
+		}
+	}
+
+	var pieces []span
+	start := s.start
+	for _, cut := range cuts {
+		pieces = append(pieces, span{start, cut})
+		start = cut
+	}
+	return append(pieces, span{start, s.end})
+}
+
+// fitTokens returns the end of the longest prefix of text[start:limit] that
+// holds at most ChunkSize tokens, and at least one character. The prefix is
+// found by doubling and then bisecting its length, so that cutting a long
+// span takes O(n log n) rather than one count per character.
+func (rc *RecursiveChunker) fitTokens(text string, start, limit int) int {
+	fits := func(end int) bool { return rc.size(text[start:end]) <= rc.ChunkSize }
+	if fits(limit) {
+		return limit
+	}
+	good, bad := start+utf8RuneLen(text[start:limit]), limit
+	for step := 16; good+step < bad; step *= 2 {
+		end := runeFloor(text, good+step)
+		if !fits(end) {
+			bad = end
+			break
+		}
+		good = end
+	}
+	for {
+		mid := runeFloor(text, good+(bad-good)/2)
+		if mid <= good {
+			return good
+		}
+		if fits(mid) {
+			good = mid
+		} else {
+			bad = mid
+		}
+	}
+}
+
+func (rc *RecursiveChunker) size(s string) int {
+	switch rc.Unit {
+	case Characters:
+		return utf8.RuneCountInString(s)
+	case Tokens:
+		if rc.Tokenizer == nil {
+			return tokenizer.NewApproxTokenizer().Count(s)
+		}
+		return rc.Tokenizer.Count(s)
+	}
+	return len(strings.Fields(s))
+}
+
+func utf8RuneLen(s string) int {
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+// runeFloor moves a byte offset back to the start of the character it is in.
+func runeFloor(text string, i int) int {
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
+
+// runeOffsets converts byte offsets into text to character offsets, counting
+// on from the previous offset when they are given in increasing order.
+type runeOffsets struct {
+	text         string
+	bytes, runes int
+}
+
+func (o *runeOffsets) at(b int) int {
+	if b < o.bytes {
+		o.bytes, o.runes = 0, 0
+	}
+	o.runes += utf8.RuneCountInString(o.text[o.bytes:b])
+	o.bytes = b
+	return o.runes
+}
+
+// wordStarts returns the byte offsets at which the words of s begin.
+func wordStarts(text string, s span) []int {
+	var starts []int
+	inSpace := true
+	for i, r := range text[s.start:s.end] {
+		space := unicode.IsSpace(r)
+		if !space && inSpace {
+			starts = append(starts, s.start+i)
+		}
+		inSpace = space
+	}
+	return starts
+}
@@ -0,0 +1,95 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/tokenizer"
+)
+
+// countingTokenizer counts one token per character and records how many
+// times it was asked to count.
+type countingTokenizer struct {
+	calls int
+}
+
+func (t *countingTokenizer) Count(text string) int {
+	t.calls++
+	return len([]rune(text))
+}
+
+func (t *countingTokenizer) Name() string { return "counting" }
+
+func TestRecursiveChunker(t *testing.T) {
+	prose := "Première phrase ici. Deuxième phrase là.\n\nÜber den Fluss. Noch ein Satz hier.\n\nLast paragraph, plain ASCII text."
+	tests := []struct {
+		name    string
+		chunker *RecursiveChunker
+		text    string
+		chunks  int
+	}{
+		{"words", NewRecursiveChunker(6, 0, Words), prose, 4},
+		{"words with overlap", NewRecursiveChunker(6, 2, Words), prose, 4},
+		{"characters", NewRecursiveChunker(30, 0, Characters), prose, 5},
+		{"tokens", NewTokenChunker(tokenizer.NewApproxTokenizer(), 12, 0), prose, 4},
+		{"hard split characters", NewRecursiveChunker(4, 0, Characters), "ééééééééé", 3},
+		{"hard split tokens", NewTokenChunker(&countingTokenizer{}, 4, 0), "日本語のテキストです", 3},
+		{"fits", NewRecursiveChunker(100, 10, Words), "  short text  ", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := tt.chunker.Chunk(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chunks) != tt.chunks {
+				t.Errorf("got %d chunks, want %d: %q", len(chunks), tt.chunks, chunks)
+			}
+			runes := []rune(tt.text)
+			last := -1
+			for i, c := range chunks {
+				if c.Position < 0 || c.Position > len(runes) || !strings.HasPrefix(string(runes[c.Position:]), c.Text) {
+					t.Errorf("chunk %d: %q is not at character %d", i, c.Text, c.Position)
+				}
+				if c.Position <= last {
+					t.Errorf("chunk %d: position %d does not follow %d", i, c.Position, last)
+				}
+				last = c.Position
+				if n := tt.chunker.size(c.Text); n > tt.chunker.ChunkSize {
+					t.Errorf("chunk %d: size %d over %d", i, n, tt.chunker.ChunkSize)
+				}
+			}
+		})
+	}
+}
+
+func TestRecursiveChunkerOverlap(t *testing.T) {
+	text := "one two three four five six seven eight nine ten"
+	chunks, err := NewRecursiveChunker(4, 2, Words).Chunk(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(chunks); i++ {
+		prev := strings.Fields(chunks[i-1].Text)
+		if !strings.HasPrefix(chunks[i].Text, strings.Join(prev[len(prev)-2:], " ")) {
+			t.Errorf("chunk %d %q does not start with the end of %q", i, chunks[i].Text, chunks[i-1].Text)
+		}
+	}
+}
+
+func TestRecursiveChunkerTokenHardSplitCost(t *testing.T) {
+	tok := &countingTokenizer{}
+	rc := NewTokenChunker(tok, 256, 0)
+	text := strings.Repeat("x", 100_000)
+	chunks, err := rc.Chunk(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (len(text) + 255) / 256; len(chunks) != want {
+		t.Errorf("got %d chunks, want %d", len(chunks), want)
+	}
+	// One count per character would be 100000 calls.
+	if tok.calls > 20*len(chunks) {
+		t.Errorf("%d Count calls for %d chunks", tok.calls, len(chunks))
+	}
+}
@@ -0,0 +1,83 @@
+package chunker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// RowChunker packs tabular records into chunks without ever splitting a record.
+//
+// It expects the output of the CSV and XLSX readers: one "header: value"
+// record per row, with records separated by a blank line. Position holds the
+// character (rune) offset of the chunk in the text, and Meta["records"] its
+// 1-based record range, e.g. "11-20".
+type RowChunker struct {
+	MaxRows  int
+	MaxWords int
+}
+
+var recordSeparator = regexp.MustCompile(`\n[ \t]*\n`)
+
+// NewRowChunker creates a RowChunker. Non-positive limits fall back to
+// 10 rows and 200 words per chunk.
+func NewRowChunker(maxRows, maxWords int) *RowChunker {
+	if maxRows <= 0 {
+		maxRows = 10
+	}
+	if maxWords <= 0 {
+		maxWords = 200
+	}
+	return &RowChunker{MaxRows: maxRows, MaxWords: maxWords}
+}
+
+func (rc *RowChunker) Chunk(text string) ([]types.Chunk, error) {
+	var chunks []types.Chunk
+	var current []string
+	wordCount := 0
+	offsets := runeOffsets{text: text}
+	first, firstRecord, records := 0, 0, 0
+
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, types.Chunk{
+				Text:     strings.Join(current, "\n\n"),
+				Position: offsets.at(first),
+				Meta:     map[string]string{"records": fmt.Sprintf("%d-%d", firstRecord, records)},
+			})
+		}
+		current = nil
+		wordCount = 0
+	}
+
+	start := 0
+	for _, sep := range append(recordSeparator.FindAllStringIndex(text, -1), []int{len(text), len(text)}) {
+		raw := text[start:sep[0]]
+		recordStart := start + len(raw) - len(strings.TrimLeft(raw, " \t\r\n"))
+		start = sep[1]
+		record := strings.TrimSpace(raw)
+		if record == "" {
+			continue
+		}
+		recordWords := len(strings.Fields(record))
+
+		if len(current) > 0 && (len(current) >= rc.MaxRows || wordCount+recordWords > rc.MaxWords) {
+			flush()
+		}
+		records++
+		if len(current) == 0 {
+			first, firstRecord = recordStart, records
+		}
+		current = append(current, record)
+		wordCount += recordWords
+	}
+	flush()
+
+	return chunks, nil
+}
+
+func (rc *RowChunker) Name() string {
+	return "row-chunker"
+}
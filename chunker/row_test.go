@@ -0,0 +1,58 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRowChunker(t *testing.T) {
+	records := []string{
+		"Name: Zoë\nAge: 31",
+		"Name: Bob\nAge: 40",
+		"Name: Cy\nNote: " + strings.Repeat("word ", 20),
+		"Name: Di",
+	}
+	text := "\n" + strings.Join(records, "\n\n \n")
+	// Character offsets of the records in text; "ë" is two bytes.
+	offsets := []int{1, 22, 43, 162}
+
+	tests := []struct {
+		name    string
+		rows    int
+		words   int
+		firsts  []int    // index of the first record of each chunk
+		records []string // Meta["records"] of each chunk
+	}{
+		{"row limit", 2, 200, []int{0, 2}, []string{"1-2", "3-4"}},
+		{"word limit", 10, 10, []int{0, 2, 3}, []string{"1-2", "3-3", "4-4"}},
+		{"one chunk", 10, 200, []int{0}, []string{"1-4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := NewRowChunker(tt.rows, tt.words).Chunk(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chunks) != len(tt.firsts) {
+				t.Fatalf("got %d chunks, want %d", len(chunks), len(tt.firsts))
+			}
+			for i, c := range chunks {
+				if want := offsets[tt.firsts[i]]; c.Position != want {
+					t.Errorf("chunk %d position = %d, want %d", i, c.Position, want)
+				}
+				if !strings.HasPrefix(c.Text, strings.TrimSpace(records[tt.firsts[i]])) {
+					t.Errorf("chunk %d starts %q, want record %d", i, c.Text, tt.firsts[i]+1)
+				}
+				if c.Meta["records"] != tt.records[i] {
+					t.Errorf("chunk %d records = %q, want %q", i, c.Meta["records"], tt.records[i])
+				}
+				for _, r := range records {
+					name := strings.SplitN(r, "\n", 2)[0]
+					if strings.Contains(c.Text, name) && !strings.Contains(c.Text, strings.TrimSpace(r)) {
+						t.Errorf("record %q split across chunks", name)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package chunker
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SentenceSegmenter splits text into sentences by rule.
+//
+// A sentence ends at terminal punctuation (. ! ? … and the full stops and
+// question marks of other scripts such as 。 ؟ ।) followed by whitespace,
+// together with any closing quotes and brackets after it. A period is not a
+// boundary inside a number, URL or version string, after a known
+// abbreviation such as "Dr." or "e.g.", after an abbreviation such as "No."
+// that is followed by a number, after an initial, or when the next word
+// starts in lower case. CJK terminators end a sentence even without
+// a following space. Blank lines and list items always start a new sentence,
+// and other line breaks do as LineBreaks says.
+type SentenceSegmenter struct {
+	// Abbreviations holds lower-cased abbreviations without their final
+	// period, e.g. "dr" and "e.g", that a period never ends a sentence after.
+	Abbreviations map[string]bool
+	// NumberAbbreviations holds abbreviations that are also common words,
+	// e.g. "no" and "art", and only count as abbreviations when a number
+	// follows, as in "No. 5" or "Sec. 3".
+	NumberAbbreviations map[string]bool
+	LineBreaks          LineBreakRule
+}
+
+// LineBreakRule decides whether a single line break after a line without
+// terminal punctuation ends a sentence.
+type LineBreakRule int
+
+const (
+	// LineBreakAuto ends the sentence when the next line starts with an
+	// upper-case letter, as after a heading or a title ("Introduction\nThis
+	// paper..."), and otherwise treats the break as a space, as in
+	// hard-wrapped prose.
+	LineBreakAuto LineBreakRule = iota
+	// LineBreakAlways ends the sentence at every line break, for
+	// line-oriented text such as lists, logs or verse.
+	LineBreakAlways
+	// LineBreakNever treats every single line break as a space.
+	LineBreakNever
+)
+
+// defaultAbbreviations are titles and reference abbreviations that are
+// usually followed by a capitalised word or a number.
+var defaultAbbreviations = []string{
+	"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "mt", "rev", "hon",
+	"maj", "capt", "lt", "sgt", "cmdr", "adm", "gov", "pres", "sen", "rep",
+	"fig", "figs", "nos", "vol", "vols", "p", "pp", "ch", "chap",
+	"eq", "eqs", "ref", "refs", "eds", "approx", "dept", "univ", "vs", "cf", "viz", "al",
+	"jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec",
+	"e.g", "i.e", "a.m", "p.m", "u.s", "u.k", "ph.d",
+}
+
+// defaultNumberAbbreviations are abbreviations that are also English words,
+// which end a sentence unless a number follows ("the answer is no.").
+var defaultNumberAbbreviations = []string{"no", "art", "sec", "gen", "col", "ed", "st"}
+
+// NewSentenceSegmenter creates a SentenceSegmenter with a list of common
+// English abbreviations.
+func NewSentenceSegmenter() *SentenceSegmenter {
+	return &SentenceSegmenter{
+		Abbreviations:       wordSet(defaultAbbreviations),
+		NumberAbbreviations: wordSet(defaultNumberAbbreviations),
+	}
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+var defaultSegmenter = NewSentenceSegmenter()
+
+// SplitSentences splits text into sentences with the default SentenceSegmenter.
+func SplitSentences(text string) []string {
+	return defaultSegmenter.Split(text)
+}
+
+// Split returns the sentences of text with their original punctuation,
+// trimmed of surrounding whitespace.
+func (s *SentenceSegmenter) Split(text string) []string {
+	spans := s.spans(text)
+	sentences := make([]string, len(spans))
+	for i, sp := range spans {
+		sentences[i] = text[sp.start:sp.end]
+	}
+	return sentences
+}
+
+var (
+	// paragraphBreak matches a blank line.
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+	// listItem matches a line that starts a bullet or numbered list item.
+	listItem = regexp.MustCompile(`^[ \t]*(?:[-*+•◦▪]|\d{1,3}[.)])[ \t]`)
+)
+
+// spans returns the byte range of every sentence in text, trimmed of
+// surrounding whitespace.
+func (s *SentenceSegmenter) spans(text string) []span {
+	var spans []span
+	add := func(start, end int) {
+		for start < end {
+			r, size := utf8.DecodeRuneInString(text[start:end])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			start += size
+		}
+		for end > start {
+			r, size := utf8.DecodeLastRuneInString(text[start:end])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end -= size
+		}
+		if start < end {
+			spans = append(spans, span{start, end})
+		}
+	}
+
+	for _, block := range s.blocks(text) {
+		start := block.start
+		for i := block.start; i < block.end; {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if !isTerminal(r) {
+				i += size
+				continue
+			}
+			end := i + size
+			// Take the rest of a run such as "?!" or "...", then any closing quotes and brackets.
+			for end < block.end {
+				next, n := utf8.DecodeRuneInString(text[end:])
+				if !isTerminal(next) && !isCloser(next) {
+					break
+				}
+				end += n
+			}
+			if s.isBoundary(text[:block.end], start, i, end) {
+				add(start, end)
+				start = end
+			}
+			i = end
+		}
+		add(start, block.end)
+	}
+	return spans
+}
+
+// blocks splits text at blank lines and before list items, which always
+// end a sentence, and at the line breaks that LineBreaks says end one.
+func (s *SentenceSegmenter) blocks(text string) []span {
+	var cuts []int
+	for _, m := range paragraphBreak.FindAllStringIndex(text, -1) {
+		cuts = append(cuts, m[1])
+	}
+	lineStart := 0
+	for i := strings.IndexByte(text, '\n'); i >= 0; {
+		if listItem.MatchString(text[i+1:]) || s.lineBreakEnds(text[lineStart:i], text[i+1:]) {
+			cuts = append(cuts, i+1)
+		}
+		lineStart = i + 1
+		next := strings.IndexByte(text[i+1:], '\n')
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	sort.Ints(cuts)
+
+	var blocks []span
+	start := 0
+	for _, cut := range cuts {
+		if cut > start {
+			blocks = append(blocks, span{start, cut})
+			start = cut
+		}
+	}
+	return append(blocks, span{start, len(text)})
+}
+
+// lineBreakEnds reports whether the line break between line and the text
+// after it ends a sentence. Lines ending in terminal punctuation are left to
+// isBoundary.
+func (s *SentenceSegmenter) lineBreakEnds(line, after string) bool {
+	line = strings.TrimRightFunc(line, unicode.IsSpace)
+	if line == "" || s.LineBreaks == LineBreakNever {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(strings.TrimRightFunc(line, isCloser))
+	if isTerminal(last) {
+		return false
+	}
+	if s.LineBreaks == LineBreakAlways {
+		return true
+	}
+	next := strings.TrimLeftFunc(after, func(r rune) bool { return r == ' ' || r == '\t' || isOpener(r) })
+	first, _ := utf8.DecodeRuneInString(next)
+	return unicode.IsUpper(first)
+}
+
+// isBoundary reports whether the terminal punctuation text[at:end] ends the
+// sentence that started at start.
+func (s *SentenceSegmenter) isBoundary(text string, start, at, end int) bool {
+	r, _ := utf8.DecodeRuneInString(text[at:])
+	if isCJKTerminal(r) {
+		return true
+	}
+	if end == len(text) {
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(text[end:])
+	if !unicode.IsSpace(next) {
+		// "3.14", "example.com", "v1.2.3", "e.g.," and "Hi!" followed by more text.
+		return false
+	}
+
+	// A following word in lower case continues the sentence ("approx. three").
+	word := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	first, _ := utf8.DecodeRuneInString(strings.TrimLeftFunc(word, isOpener))
+	if unicode.IsLower(first) {
+		return false
+	}
+
+	if r != '.' || end-at > 1 {
+		return true
+	}
+	token := text[start:at]
+	if i := strings.LastIndexFunc(token, unicode.IsSpace); i >= 0 {
+		token = token[i+1:]
+	}
+	token = strings.TrimLeftFunc(token, isOpener)
+	abbr := strings.ToLower(token)
+	if s.Abbreviations[abbr] || (s.NumberAbbreviations[abbr] && unicode.IsDigit(first)) {
+		return false
+	}
+	// Initials such as the "J" of "J. Smith".
+	if first, size := utf8.DecodeRuneInString(token); size == len(token) && unicode.IsUpper(first) {
+		return false
+	}
+	return true
+}
+
+// isTerminal reports whether r is sentence-ending punctuation in any script.
+func isTerminal(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '‼', '‽', '⁇', '⁈', '⁉',
+		'؟', '۔', '।', '॥', '։', '።', '፧', '᙮', '܀', '׃':
+		return true
+	}
+	return isCJKTerminal(r)
+}
+
+// isCJKTerminal reports whether r is a full-width terminator, which needs no
+// following space.
+func isCJKTerminal(r rune) bool {
+	switch r {
+	case '。', '！', '？', '｡', '．', '︒', '﹒', '﹖', '﹗':
+		return true
+	}
+	return false
+}
+
+func isCloser(r rune) bool {
+	switch r {
+	case ')', ']', '}', '"', '\'', '”', '’', '»', '›', '」', '』', '）', '】', '〉', '》':
+		return true
+	}
+	return false
+}
+
+func isOpener(r rune) bool {
+	switch r {
+	case '(', '[', '{', '"', '\'', '“', '‘', '«', '‹', '「', '『', '（', '【', '〈', '《':
+		return true
+	}
+	return false
+}
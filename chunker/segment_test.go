@@ -0,0 +1,65 @@
+package chunker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSentenceSegmenter(t *testing.T) {
+	tests := []struct {
+		name string
+		rule LineBreakRule
+		text string
+		want []string
+	}{
+		{"simple", LineBreakAuto, "One. Two! Three?", []string{"One.", "Two!", "Three?"}},
+		{"abbreviation", LineBreakAuto, "Dr. Smith arrived. He left.", []string{"Dr. Smith arrived.", "He left."}},
+		{"e.g.", LineBreakAuto, "Use tools, e.g. Go. Then stop.", []string{"Use tools, e.g. Go.", "Then stop."}},
+		{"number abbreviation", LineBreakAuto, "See No. 5 and Sec. 3. Then stop.", []string{"See No. 5 and Sec. 3.", "Then stop."}},
+		{"word like an abbreviation", LineBreakAuto, "The answer is no. We left.", []string{"The answer is no.", "We left."}},
+		{"word like an abbreviation before a name", LineBreakAuto, "I like art. It helps.", []string{"I like art.", "It helps."}},
+		{"initial", LineBreakAuto, "J. Smith wrote it. Done.", []string{"J. Smith wrote it.", "Done."}},
+		{"numbers urls versions", LineBreakAuto, "Pi is 3.14 and v1.2.3 is at example.com. Next.", []string{"Pi is 3.14 and v1.2.3 is at example.com.", "Next."}},
+		{"lower case continues", LineBreakAuto, "It costs approx. three euros.", []string{"It costs approx. three euros."}},
+		{"quotes and runs", LineBreakAuto, `He said "Stop!" Then "Why?!" Done...`, []string{`He said "Stop!"`, `Then "Why?!"`, "Done..."}},
+		{"cjk", LineBreakAuto, "これは文です。次の文です。", []string{"これは文です。", "次の文です。"}},
+		{"blank line", LineBreakAuto, "First line\n\nsecond paragraph", []string{"First line", "second paragraph"}},
+		{"list items", LineBreakAuto, "Steps:\n- install it\n- run it", []string{"Steps:", "- install it", "- run it"}},
+		{"heading line", LineBreakAuto, "Introduction\nThis paper studies chunking.", []string{"Introduction", "This paper studies chunking."}},
+		{"hard wrap", LineBreakAuto, "This sentence is\nwrapped across lines.", []string{"This sentence is\nwrapped across lines."}},
+		{"always", LineBreakAlways, "roses are red\nviolets are blue", []string{"roses are red", "violets are blue"}},
+		{"never", LineBreakNever, "Introduction\nThis paper studies chunking.", []string{"Introduction\nThis paper studies chunking."}},
+		{"punctuated line left to the period rule", LineBreakAlways, "See e.g.\nThe manual.", []string{"See e.g.\nThe manual."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSentenceSegmenter()
+			s.LineBreaks = tt.rule
+			if got := s.Split(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentenceChunker(t *testing.T) {
+	text := "Première phrase ici. Deuxième phrase là. Dritte Satz hier. Vierte."
+	chunks, err := NewSentenceChunker(6).Chunk(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Première phrase ici. Deuxième phrase là.", "Dritte Satz hier. Vierte."}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(chunks), len(want))
+	}
+	runes := []rune(text)
+	for i, c := range chunks {
+		if c.Text != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, c.Text, want[i])
+		}
+		if !strings.HasPrefix(string(runes[c.Position:]), c.Text) {
+			t.Errorf("chunk %d is not at character %d", i, c.Position)
+		}
+	}
+}
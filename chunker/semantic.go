@@ -0,0 +1,193 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Ashank007/docai/embedder"
+	"github.com/Ashank007/docai/tokenizer"
+	"github.com/Ashank007/docai/types"
+	"github.com/Ashank007/docai/utils"
+)
+
+// SemanticChunker groups sentences into topic-coherent chunks. Every
+// sentence is embedded together with BufferSize sentences on either side,
+// and a new chunk starts wherever the cosine similarity between neighbouring
+// sentences falls below the Percentile-th percentile of all neighbour
+// similarities in the text, or where either sentence embeds to a zero
+// vector. A chunk is also cut before it would exceed MaxTokens, as counted
+// by Tokenizer, and a single sentence longer than that is split by tokens.
+//
+// Position holds the character (rune) offset of the chunk in the source text.
+type SemanticChunker struct {
+	Embedder   embedder.Embedder
+	Percentile float64 // 0-100; lower values split less often
+	BufferSize int
+	MaxTokens  int
+	Tokenizer  tokenizer.Tokenizer // nil uses tokenizer.ApproxTokenizer
+	Segmenter  *SentenceSegmenter  // nil uses the default segmenter
+}
+
+// NewSemanticChunker creates a SemanticChunker that splits at the lowest 10%
+// of neighbour similarities, embeds each sentence with one neighbour on
+// either side, and caps chunks at maxTokens (512 when non-positive).
+func NewSemanticChunker(emb embedder.Embedder, maxTokens int) *SemanticChunker {
+	if maxTokens <= 0 {
+		maxTokens = 512
+	}
+	return &SemanticChunker{
+		Embedder:   emb,
+		Percentile: 10,
+		BufferSize: 1,
+		MaxTokens:  maxTokens,
+		Tokenizer:  tokenizer.NewApproxTokenizer(),
+		Segmenter:  defaultSegmenter,
+	}
+}
+
+func (sc *SemanticChunker) Chunk(text string) ([]types.Chunk, error) {
+	return sc.ChunkContext(context.Background(), text)
+}
+
+// ChunkContext is Chunk with the sentence embeddings bound to ctx.
+func (sc *SemanticChunker) ChunkContext(ctx context.Context, text string) ([]types.Chunk, error) {
+	segmenter := sc.Segmenter
+	if segmenter == nil {
+		segmenter = defaultSegmenter
+	}
+	tok := sc.Tokenizer
+	if tok == nil {
+		tok = tokenizer.NewApproxTokenizer()
+	}
+
+	sentences := segmenter.spans(text)
+	if len(sentences) == 0 {
+		return nil, nil
+	}
+
+	similarities, err := sc.similarities(ctx, text, sentences)
+	if err != nil {
+		return nil, err
+	}
+	threshold := percentile(similarities, sc.Percentile)
+
+	var chunks []types.Chunk
+	offsets := runeOffsets{text: text}
+	emit := func(s span) error {
+		position := offsets.at(s.start)
+		if tok.Count(text[s.start:s.end]) <= sc.MaxTokens {
+			chunks = append(chunks, types.Chunk{Text: text[s.start:s.end], Position: position})
+			return nil
+		}
+		// Only a single sentence can be this long; cut it by tokens.
+		pieces, err := NewTokenChunker(tok, sc.MaxTokens, 0).Chunk(text[s.start:s.end])
+		if err != nil {
+			return fmt.Errorf("failed to split a long sentence: %w", err)
+		}
+		for _, piece := range pieces {
+			piece.Position += position
+			chunks = append(chunks, piece)
+		}
+		return nil
+	}
+	current := sentences[0]
+	for i, sentence := range sentences[1:] {
+		// A NaN similarity, from a zero vector, always starts a new chunk.
+		topicShift := !(similarities[i] >= threshold)
+		tooLong := tok.Count(text[current.start:sentence.end]) > sc.MaxTokens
+		if topicShift || tooLong {
+			if err := emit(current); err != nil {
+				return nil, err
+			}
+			current = sentence
+			continue
+		}
+		current.end = sentence.end
+	}
+	if err := emit(current); err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+func (sc *SemanticChunker) Name() string {
+	return "semantic-chunker"
+}
+
+// embedBatchSize is the number of sentence windows embedded per request.
+const embedBatchSize = 64
+
+// similarities embeds every sentence with its surrounding window, in one
+// batch when the Embedder supports it, and returns the cosine similarity
+// between each sentence and the next. The similarity is NaN when either
+// vector is zero, as some embedders return for empty or unknown text.
+func (sc *SemanticChunker) similarities(ctx context.Context, text string, sentences []span) ([]float64, error) {
+	if len(sentences) < 2 {
+		return nil, nil
+	}
+	buffer := max(sc.BufferSize, 0)
+
+	windows := make([]string, len(sentences))
+	for i := range sentences {
+		window := text[sentences[max(0, i-buffer)].start:sentences[min(len(sentences)-1, i+buffer)].end]
+		windows[i] = strings.TrimSpace(window)
+	}
+	var vectors [][]float32
+	for start := 0; start < len(windows); start += embedBatchSize {
+		batch := windows[start:min(start+embedBatchSize, len(windows))]
+		batchVectors, err := embedder.EmbedBatchContext(ctx, sc.Embedder, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed sentences %d-%d: %w", start+1, start+len(batch), err)
+		}
+		if len(batchVectors) != len(batch) {
+			return nil, fmt.Errorf("got %d embeddings for %d sentences", len(batchVectors), len(batch))
+		}
+		vectors = append(vectors, batchVectors...)
+	}
+
+	similarities := make([]float64, len(sentences)-1)
+	for i := range similarities {
+		if isZero(vectors[i]) || isZero(vectors[i+1]) {
+			similarities[i] = math.NaN()
+			continue
+		}
+		sim, err := utils.CosineSimilarity(vectors[i], vectors[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare sentences %d and %d: %w", i+1, i+2, err)
+		}
+		similarities[i] = sim
+	}
+	return similarities, nil
+}
+
+func isZero(vec []float32) bool {
+	for _, v := range vec {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// percentile returns the p-th percentile of values, ignoring NaNs and
+// interpolating linearly between the closest ranks. It returns -Inf for no
+// values.
+func percentile(values []float64, p float64) float64 {
+	var sorted []float64
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 {
+		return math.Inf(-1)
+	}
+	sort.Float64s(sorted)
+	rank := math.Max(0, math.Min(100, p)) / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
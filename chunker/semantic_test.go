@@ -0,0 +1,242 @@
+package chunker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// topicEmbedder embeds text by the topic words it mentions, so sentences on
+// the same topic are similar and sentences on different topics are not.
+type topicEmbedder struct {
+	topics []string
+	calls  int
+}
+
+func (e *topicEmbedder) Embed(text string) ([]float32, error) {
+	e.calls++
+	vec := make([]float32, len(e.topics)+1)
+	vec[len(e.topics)] = 0.01
+	for i, topic := range e.topics {
+		vec[i] = float32(strings.Count(strings.ToLower(text), topic))
+	}
+	return vec, nil
+}
+
+func (e *topicEmbedder) Name() string { return "topic" }
+
+// failingEmbedder fails every request.
+type failingEmbedder struct{}
+
+func (failingEmbedder) Embed(string) ([]float32, error) { return nil, errors.New("model down") }
+func (failingEmbedder) Name() string                    { return "failing" }
+
+func TestSemanticChunker(t *testing.T) {
+	text := "Cats purr. Cats nap all day. Cats chase mice. " +
+		"Über Rust: Rust has borrow checks. Rust compiles fast. Rust is safe."
+	tests := []struct {
+		name       string
+		maxTokens  int
+		percentile float64
+		want       []string
+	}{
+		{
+			name:       "topic shift",
+			maxTokens:  512,
+			percentile: 10,
+			want: []string{
+				"Cats purr. Cats nap all day. Cats chase mice.",
+				"Über Rust: Rust has borrow checks. Rust compiles fast. Rust is safe.",
+			},
+		},
+		{
+			name:       "token cap",
+			maxTokens:  8,
+			percentile: 0,
+			want: []string{
+				"Cats purr. Cats nap all day.",
+				"Cats chase mice.",
+				// The sentence is longer than the cap on its own, so it is split by tokens.
+				"Über Rust: Rust has",
+				"borrow checks.",
+				"Rust compiles fast.",
+				"Rust is safe.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emb := &topicEmbedder{topics: []string{"cats", "rust"}}
+			sc := NewSemanticChunker(emb, tt.maxTokens)
+			sc.Percentile = tt.percentile
+			sc.BufferSize = 0
+			chunks, err := sc.Chunk(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			runes := []rune(text)
+			for _, c := range chunks {
+				got = append(got, c.Text)
+				if !strings.HasPrefix(string(runes[c.Position:]), c.Text) {
+					t.Errorf("chunk %q is not at character %d", c.Text, c.Position)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if emb.calls != 6 {
+				t.Errorf("embedded %d sentences, want 6", emb.calls)
+			}
+		})
+	}
+}
+
+// wordEmbedder embeds text by how often it mentions word, so text without
+// the word embeds to a zero vector.
+type wordEmbedder string
+
+func (e wordEmbedder) Embed(text string) ([]float32, error) {
+	return []float32{float32(strings.Count(strings.ToLower(text), string(e)))}, nil
+}
+func (e wordEmbedder) Name() string { return "word" }
+
+func TestSemanticChunkerZeroVector(t *testing.T) {
+	sc := NewSemanticChunker(wordEmbedder("cats"), 512)
+	sc.BufferSize = 0
+	chunks, err := sc.Chunk("Cats purr. Hmm. Cats nap.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Text)
+	}
+	// The zero vector of "Hmm." breaks the chunk on both sides of it.
+	if want := []string{"Cats purr.", "Hmm.", "Cats nap."}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSemanticChunkerEdges(t *testing.T) {
+	chunks, err := NewSemanticChunker(failingEmbedder{}, 100).Chunk("  ")
+	if err != nil || len(chunks) != 0 {
+		t.Errorf("empty text: got %v, %v", chunks, err)
+	}
+	chunks, err = NewSemanticChunker(failingEmbedder{}, 100).Chunk("Only one sentence.")
+	if err != nil || len(chunks) != 1 {
+		t.Errorf("one sentence: got %v, %v", chunks, err)
+	}
+	if _, err := NewSemanticChunker(failingEmbedder{}, 100).Chunk("One. Two."); err == nil {
+		t.Error("embedding failure was not returned")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{0.9, 0.1, 0.5, 0.3}
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 0.1},
+		{100, 0.9},
+		{50, 0.4},
+		{-5, 0.1},
+	}
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); !math.IsInf(got, -1) {
+		t.Errorf("percentile(nil) = %v, want -Inf", got)
+	}
+	if got := percentile([]float64{math.NaN(), 0.2, math.NaN(), 0.6}, 50); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("percentile() with NaNs = %v, want 0.4", got)
+	}
+}
+
+type ctxKey struct{}
+
+// ctxEmbedder is a batch embedder that records the context value of every
+// batch it is asked to embed.
+type ctxEmbedder struct {
+	topicEmbedder
+	seen []any
+}
+
+func (e *ctxEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	return e.EmbedBatchContext(context.Background(), texts)
+}
+
+func (e *ctxEmbedder) EmbedBatchContext(ctx context.Context, texts []string) ([][]float32, error) {
+	e.seen = append(e.seen, ctx.Value(ctxKey{}))
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(text)
+	}
+	return vectors, nil
+}
+
+func TestSemanticChunkerContext(t *testing.T) {
+	text := "Cats purr. Cats nap. Rust is safe. Rust is fast."
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "run")
+	emb := &ctxEmbedder{topicEmbedder: topicEmbedder{topics: []string{"cats", "rust"}}}
+	if _, err := NewSemanticChunker(emb, 512).ChunkContext(ctx, text); err != nil {
+		t.Fatal(err)
+	}
+	if len(emb.seen) != 1 || emb.seen[0] != "run" {
+		t.Errorf("embedder saw contexts %v, want the caller's", emb.seen)
+	}
+	emb.seen = nil
+	if _, err := NewParentChildChunker(NewSentenceChunker(512), NewSemanticChunker(emb, 512)).ChunkFamiliesContext(ctx, text); err != nil {
+		t.Fatal(err)
+	}
+	if len(emb.seen) != 1 || emb.seen[0] != "run" {
+		t.Errorf("child chunker saw contexts %v, want the caller's", emb.seen)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name  string
+		chunk func(*SemanticChunker) ([]types.Chunk, error)
+	}{
+		{"ChunkContext", func(sc *SemanticChunker) ([]types.Chunk, error) { return sc.ChunkContext(cancelled, text) }},
+		{"ChunkContext helper", func(sc *SemanticChunker) ([]types.Chunk, error) { return ChunkContext(cancelled, sc, text) }},
+		{"ChunkPagesContext", func(sc *SemanticChunker) ([]types.Chunk, error) {
+			return ChunkPagesContext(cancelled, sc, []string{text})
+		}},
+		{"ChunkSectionsContext", func(sc *SemanticChunker) ([]types.Chunk, error) {
+			return NewStructureChunker(sc).ChunkSectionsContext(cancelled, []types.Section{{Text: text}})
+		}},
+		{"ChunkFamiliesContext parent", func(sc *SemanticChunker) ([]types.Chunk, error) {
+			_, err := ChunkFamiliesContext(cancelled, NewParentChildChunker(sc, NewSentenceChunker(1)), text)
+			return nil, err
+		}},
+		{"ChunkFamiliesContext child", func(sc *SemanticChunker) ([]types.Chunk, error) {
+			_, err := ChunkFamiliesContext(cancelled, NewParentChildChunker(NewSentenceChunker(512), sc), text)
+			return nil, err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A plain embedder is not called once the context is done.
+			emb := &topicEmbedder{topics: []string{"cats", "rust"}}
+			_, err := tt.chunk(NewSemanticChunker(emb, 512))
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want context.Canceled", err)
+			}
+			if emb.calls != 0 {
+				t.Errorf("embedder called %d times after cancel", emb.calls)
+			}
+		})
+	}
+}
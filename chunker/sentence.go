@@ -6,49 +6,58 @@ import (
 	"github.com/Ashank007/docai/types"
 )
 
+// SentenceChunker packs whole sentences into chunks of at most MaxWords
+// words. Sentences are found by Segmenter, and a sentence longer than
+// MaxWords becomes a chunk of its own. Position holds the character (rune)
+// offset of the chunk in the source text.
 type SentenceChunker struct {
-	MaxWords int
+	MaxWords  int
+	Segmenter *SentenceSegmenter
 }
 
 func NewSentenceChunker(maxWords int) *SentenceChunker {
 	if maxWords <= 0 {
 		maxWords = 200
 	}
-	return &SentenceChunker{MaxWords: maxWords}
+	return &SentenceChunker{MaxWords: maxWords, Segmenter: defaultSegmenter}
 }
 
 func (sc *SentenceChunker) Chunk(text string) ([]types.Chunk, error) {
-	rawSentences := strings.Split(text, ".")
+	segmenter := sc.Segmenter
+	if segmenter == nil {
+		segmenter = defaultSegmenter
+	}
+
 	var chunks []types.Chunk
-	var currentChunk []string
+	offsets := runeOffsets{text: text}
+	current := span{-1, -1}
 	wordCount := 0
 
-	for _, s := range rawSentences {
-		s = strings.TrimSpace(s)
-		if s == "" {
-			continue
+	flush := func() {
+		if current.start >= 0 {
+			chunks = append(chunks, types.Chunk{
+				Text:     text[current.start:current.end],
+				Position: offsets.at(current.start),
+			})
 		}
+		current = span{-1, -1}
+		wordCount = 0
+	}
 
-		sentence := s + "."
-		sentenceWords := len(strings.Fields(sentence))
+	for _, sentence := range segmenter.spans(text) {
+		sentenceWords := len(strings.Fields(text[sentence.start:sentence.end]))
 
-		if wordCount+sentenceWords > sc.MaxWords && len(currentChunk) > 0 {
-			chunks = append(chunks, types.Chunk{
-				Text: strings.Join(currentChunk, " "),
-			})
-			currentChunk = []string{}
-			wordCount = 0
+		if wordCount+sentenceWords > sc.MaxWords && current.start >= 0 {
+			flush()
 		}
 
-		currentChunk = append(currentChunk, sentence)
+		if current.start < 0 {
+			current.start = sentence.start
+		}
+		current.end = sentence.end
 		wordCount += sentenceWords
 	}
-
-	if len(currentChunk) > 0 {
-		chunks = append(chunks, types.Chunk{
-			Text: strings.Join(currentChunk, " "),
-		})
-	}
+	flush()
 
 	return chunks, nil
 }
@@ -0,0 +1,67 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// StructureChunker chunks the sections of a structured document one at a
+// time with an inner Chunker, so that no chunk spans two sections, and
+// records the heading path and page of its section on every chunk.
+// Positions are relative to the section text.
+type StructureChunker struct {
+	Chunker Chunker
+}
+
+// NewStructureChunker creates a StructureChunker that splits each section
+// with inner.
+func NewStructureChunker(inner Chunker) *StructureChunker {
+	return &StructureChunker{Chunker: inner}
+}
+
+// ChunkSections chunks each section and stamps its chunks with the section's
+// heading path and, when known, its page.
+func (sc *StructureChunker) ChunkSections(sections []types.Section) ([]types.Chunk, error) {
+	return sc.ChunkSectionsContext(context.Background(), sections)
+}
+
+// ChunkSectionsContext is ChunkSections with every section chunked by ChunkContext.
+func (sc *StructureChunker) ChunkSectionsContext(ctx context.Context, sections []types.Section) ([]types.Chunk, error) {
+	var chunks []types.Chunk
+	for i, section := range sections {
+		if strings.TrimSpace(section.Text) == "" {
+			continue
+		}
+		sectionChunks, err := ChunkContext(ctx, sc.Chunker, section.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chunk section %d: %w", i+1, err)
+		}
+		for _, chunk := range sectionChunks {
+			if len(section.Headings) > 0 {
+				chunk.Headings = append([]string(nil), section.Headings...)
+			}
+			if section.Page > 0 {
+				chunk.Page = section.Page
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, nil
+}
+
+// Chunk chunks unstructured text with the inner Chunker.
+func (sc *StructureChunker) Chunk(text string) ([]types.Chunk, error) {
+	return sc.Chunker.Chunk(text)
+}
+
+// ChunkContext chunks unstructured text with the inner Chunker, bound to ctx.
+func (sc *StructureChunker) ChunkContext(ctx context.Context, text string) ([]types.Chunk, error) {
+	return ChunkContext(ctx, sc.Chunker, text)
+}
+
+func (sc *StructureChunker) Name() string {
+	return "structure-chunker"
+}
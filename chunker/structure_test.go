@@ -0,0 +1,45 @@
+package chunker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+func TestStructureChunkerChunkSections(t *testing.T) {
+	sections := []types.Section{
+		{Headings: []string{"Guide"}, Text: "One. Two. Three."},
+		{Headings: []string{"Guide", "Install"}, Text: "  "},
+		{Text: "Untitled text."},
+		{Headings: []string{"Ch 2"}, Text: "Chapter text.", Page: 2},
+	}
+	chunks, err := NewStructureChunker(NewSentenceChunker(2)).ChunkSections(sections)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type got struct {
+		Text     string
+		Headings []string
+		Page     int
+	}
+	want := []got{
+		{"One. Two.", []string{"Guide"}, 0},
+		{"Three.", []string{"Guide"}, 0},
+		{"Untitled text.", nil, 0},
+		{"Chapter text.", []string{"Ch 2"}, 2},
+	}
+	var have []got
+	for _, c := range chunks {
+		have = append(have, got{c.Text, c.Headings, c.Page})
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+
+	// Chunks must not share the section's heading slice.
+	chunks[0].Headings[0] = "changed"
+	if chunks[1].Headings[0] != "Guide" || sections[0].Headings[0] != "Guide" {
+		t.Error("chunks share their heading path")
+	}
+}
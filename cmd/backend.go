@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+
+	"github.com/Ashank007/docai/embedder"
+	"github.com/Ashank007/docai/generator"
+)
+
+// newBackend returns the embedder and generator the CLI talks to, along with
+// the key of the embedder's vectors in the embedding cache. Ollama on
+// localhost is used unless DOCAI_OPENAI_BASE_URL points at an
+// OpenAI-compatible server:
+//
+//	DOCAI_OPENAI_BASE_URL    API root, e.g. https://api.openai.com/v1
+//	DOCAI_OPENAI_API_KEY     API key, falls back to OPENAI_API_KEY
+//	DOCAI_OPENAI_KEY_HEADER  header carrying the key instead of "Authorization: Bearer"
+//	DOCAI_EMBED_MODEL        embedding model
+//	DOCAI_CHAT_MODEL         chat model
+func newBackend() (embedder.ContextBatchEmbedder, generator.ContextGenerator, string) {
+	baseURL := os.Getenv("DOCAI_OPENAI_BASE_URL")
+	if baseURL == "" {
+		embed := embedder.NewOllama(envOr("DOCAI_EMBED_MODEL", "nomic-embed-text"), "http://localhost:11434/api/embeddings")
+		gen := generator.NewOllama(envOr("DOCAI_CHAT_MODEL", "llama3.1"), "http://localhost:11434/api/generate")
+		return embed, gen, embed.CacheKey()
+	}
+
+	apiKey := envOr("DOCAI_OPENAI_API_KEY", os.Getenv("OPENAI_API_KEY"))
+	keyHeader := os.Getenv("DOCAI_OPENAI_KEY_HEADER")
+	embed := embedder.NewOpenAI(envOr("DOCAI_EMBED_MODEL", "text-embedding-3-small"), baseURL, apiKey)
+	embed.APIKeyHeader = keyHeader
+	gen := generator.NewOpenAI(envOr("DOCAI_CHAT_MODEL", "gpt-4o-mini"), baseURL, apiKey)
+	gen.APIKeyHeader = keyHeader
+	return embed, gen, embed.CacheKey()
+}
+
+// envOr returns the environment variable key, or fallback when it is unset or empty.
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
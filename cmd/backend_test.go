@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestNewBackendCacheKey(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"ollama", nil, "ollama:nomic-embed-text@http://localhost:11434"},
+		{"ollama model", map[string]string{"DOCAI_EMBED_MODEL": "mxbai-embed-large"}, "ollama:mxbai-embed-large@http://localhost:11434"},
+		{"openai", map[string]string{"DOCAI_OPENAI_BASE_URL": "http://localhost:8080/v1"}, "openai:text-embedding-3-small@http://localhost:8080/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"DOCAI_OPENAI_BASE_URL", "DOCAI_EMBED_MODEL"} {
+				t.Setenv(k, tt.env[k])
+			}
+			if _, _, key := newBackend(); key != tt.want {
+				t.Errorf("cache key = %q, want %q", key, tt.want)
+			}
+		})
+	}
+}
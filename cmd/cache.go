@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ashank007/docai/store"
+)
+
+const cacheUsage = `usage: docai cache <command>
+
+commands:
+  stats                                    show the number and size of cached embeddings per cache key
+  evict [-unused-for 720h] [-max 100000]   remove stale or least recently used embeddings
+  clear [key]                              remove all cached embeddings, or those under one key from stats
+  vacuum                                   return freed space to the file system`
+
+// runCacheCommand runs a "docai cache" subcommand against the database at dbPath.
+func runCacheCommand(dbPath string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing cache command\n%s", cacheUsage)
+	}
+
+	meta := store.NewSQLiteStore()
+	if err := meta.Init(dbPath); err != nil {
+		return fmt.Errorf("failed to open %s: %w", dbPath, err)
+	}
+	defer meta.Close()
+	cache, err := store.NewSQLiteEmbeddingCache(meta.DB())
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "stats":
+		stats, err := cache.Stats()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d cached embeddings, %.1f MiB, %d hits served\n", stats.Entries, float64(stats.Bytes)/(1<<20), stats.Hits)
+		keys := make([]string, 0, len(stats.Keys))
+		for key := range stats.Keys {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %-60s %d\n", key, stats.Keys[key])
+		}
+
+	case "evict":
+		fs := flag.NewFlagSet("evict", flag.ContinueOnError)
+		unusedFor := fs.Duration("unused-for", 0, "remove embeddings not used for this long")
+		maxEntries := fs.Int("max", 0, "keep at most this many of the most recently used embeddings")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *unusedFor <= 0 && *maxEntries <= 0 {
+			return fmt.Errorf("evict needs -unused-for or -max\n%s", cacheUsage)
+		}
+		var before time.Time
+		if *unusedFor > 0 {
+			before = time.Now().Add(-*unusedFor)
+		}
+		removed, err := cache.Evict(before, *maxEntries)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached embeddings. Run 'docai cache vacuum' to shrink the database file.\n", removed)
+
+	case "clear":
+		key := ""
+		if len(args) > 1 {
+			key = args[1]
+		}
+		removed, err := cache.Clear(key)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached embeddings.\n", removed)
+
+	case "vacuum":
+		if err := cache.Vacuum(); err != nil {
+			return err
+		}
+		fmt.Println("Database vacuumed.")
+
+	default:
+		return fmt.Errorf("unknown cache command %q\n%s", args[0], cacheUsage)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Ashank007/docai/chain"
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/reader"
+)
+
+// errExtract marks indexing failures caused by reading a file rather than by
+// embedding or storing it, so that the CLI can skip the file and carry on.
+var errExtract = errors.New("file extract failed")
+
+// indexer indexes files through the embed chain, choosing how to extract and
+// chunk each one by its reader. Archive members go through the same choice
+// as top-level files. Every file is indexed with its own copy of embedChain
+// and codeChunker, so an indexer keeps no state between files.
+type indexer struct {
+	readers    *reader.Registry
+	embedChain *chain.EmbedChain
+
+	chunker          chunker.Chunker // mailbox messages
+	rowChunker       chunker.Chunker // CSV and XLSX
+	semanticChunker  chunker.Chunker // plain text
+	parentChunker    chunker.Chunker // everything else
+	codeChunker      *chunker.CodeChunker
+	structureChunker *chunker.StructureChunker
+}
+
+// index extracts, chunks and embeds filePath under docName and returns its
+// page count, 0 for formats without pages. meta is added to the metadata of
+// every chunk.
+func (ix *indexer) index(ctx context.Context, docName, filePath string, meta map[string]string) (int, error) {
+	rd, err := ix.readers.Lookup(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errExtract, err)
+	}
+	ec := *ix.embedChain
+	ec.DocName = docName
+	ec.Meta = meta
+
+	switch rd := rd.(type) {
+	case *reader.ArchiveReader:
+		return 0, ix.indexArchive(ctx, rd, docName, filePath)
+
+	// Files holding several documents, such as mailboxes, index each one under its own name.
+	case reader.DocumentReader:
+		docs, err := reader.ExtractDocumentsContext(ctx, rd, filePath)
+		if err != nil {
+			return 0, fmt.Errorf("%w for %s: %w", errExtract, docName, err)
+		}
+		ec.Chunker = ix.chunker
+		for _, doc := range docs {
+			ec.DocName = docName + doc.Name
+			ec.Meta = mergeMeta(meta, doc.Meta)
+			if _, err := ec.RunContext(ctx, reader.NormalizeText(doc.Text)); err != nil {
+				return 0, fmt.Errorf("EmbedChain failed for %s: %w", ec.DocName, err)
+			}
+		}
+		fmt.Printf("✅ Document '%s' indexed for querying successfully (%d documents).\n", docName, len(docs))
+		return 0, nil
+
+	// Structured formats such as Markdown, DOCX and EPUB keep the heading
+	// path of every chunk, and the page or chapter where the reader knows it.
+	case reader.SectionReader:
+		sections, err := reader.ExtractSectionsContext(ctx, rd, filePath)
+		if err != nil {
+			return 0, fmt.Errorf("%w for %s: %w", errExtract, docName, err)
+		}
+		for i := range sections {
+			sections[i].Text = reader.NormalizeText(sections[i].Text)
+		}
+		chunks, err := ix.structureChunker.ChunkSectionsContext(ctx, sections)
+		if err != nil {
+			return 0, fmt.Errorf("chunking failed for %s: %w", docName, err)
+		}
+		if _, err := ec.RunChunksContext(ctx, chunks); err != nil {
+			return 0, fmt.Errorf("EmbedChain failed for %s: %w", docName, err)
+		}
+		pageCount := 0
+		for _, s := range sections {
+			pageCount = max(pageCount, s.Page)
+		}
+		fmt.Printf("✅ Document '%s' indexed for querying successfully (%d sections).\n", docName, len(sections))
+		return pageCount, nil
+	}
+
+	// Paged formats such as PDF are chunked page by page so chunks keep their page number.
+	pr, paged := rd.(reader.PageReader)
+	var pages []string
+	if paged {
+		pages, err = reader.ExtractPagesContext(ctx, pr, filePath)
+	} else {
+		var text string
+		text, err = reader.ExtractContext(ctx, rd, filePath)
+		pages = []string{text}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w for %s: %w", errExtract, docName, err)
+	}
+	for i := range pages {
+		pages[i] = reader.NormalizeText(pages[i])
+	}
+
+	// Tabular formats are chunked by whole rows so a record is never split.
+	ec.Chunker = ix.parentChunker
+	switch rd.(type) {
+	case *reader.CSVReader, *reader.XLSXReader:
+		ec.Chunker = ix.rowChunker
+	case *reader.TextReader:
+		ec.Chunker = ix.semanticChunker
+	case *reader.CodeReader:
+		// Source files are split on declarations rather than on '.'.
+		cc := *ix.codeChunker
+		cc.Language = reader.CodeLanguage(filePath)
+		ec.Chunker = &cc
+	}
+
+	if paged {
+		_, err = ec.RunPagesContext(ctx, pages)
+	} else {
+		_, err = ec.RunContext(ctx, pages[0])
+	}
+	if err != nil {
+		return 0, fmt.Errorf("EmbedChain failed for %s: %w", docName, err)
+	}
+	fmt.Printf("✅ Document '%s' indexed for querying successfully.\n", docName)
+	if paged {
+		return len(pages), nil
+	}
+	return 0, nil
+}
+
+// indexArchive indexes every supported member of an archive under
+// "archive.zip!path/inside.pdf", as if it were a file of its own. Members
+// that cannot be read are skipped.
+func (ix *indexer) indexArchive(ctx context.Context, ar *reader.ArchiveReader, docName, filePath string) error {
+	var indexErr error
+	members := 0
+	err := ar.WalkMembers(ctx, filePath, func(name, memberPath string) error {
+		meta := map[string]string{"path": strings.TrimPrefix(name, "!")}
+		if rd, err := ix.readers.Lookup(memberPath); err == nil {
+			if mr, ok := rd.(reader.MetadataReader); ok {
+				if m, err := mr.ExtractMetadata(memberPath); err == nil {
+					meta = mergeMeta(m, meta)
+				}
+			}
+		}
+		_, err := ix.index(ctx, docName+name, memberPath, meta)
+		switch {
+		case errors.Is(err, errExtract):
+			log.Printf("⚠️ %v. Skipping archive member.\n", err)
+			return nil
+		case err != nil:
+			indexErr = err
+			return err
+		}
+		members++
+		return nil
+	})
+	if indexErr != nil {
+		return indexErr
+	}
+	if err != nil {
+		return fmt.Errorf("%w for %s: %w", errExtract, docName, err)
+	}
+	fmt.Printf("✅ Archive '%s' indexed for querying successfully (%d files).\n", docName, members)
+	return nil
+}
+
+// mergeMeta returns base overlaid with extra, or nil if both are empty.
+func mergeMeta(base, extra map[string]string) map[string]string {
+	if len(base) == 0 {
+		return extra
+	}
+	if len(extra) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
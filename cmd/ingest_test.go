@@ -0,0 +1,197 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ashank007/docai/chain"
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+// recordingStore is a MetadataStore that keeps saved chunks by document name.
+type recordingStore struct {
+	mu     sync.Mutex
+	chunks map[string][]types.Chunk
+}
+
+func (s *recordingStore) Init(string) error { return nil }
+func (s *recordingStore) SaveFile(f types.FileMeta) (types.FileMeta, error) {
+	return f, nil
+}
+func (s *recordingStore) SaveChunk(docName string, c types.Chunk) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chunks[docName] = append(s.chunks[docName], c)
+	return int64(len(s.chunks[docName])), nil
+}
+func (s *recordingStore) GetChunkByID(int64) (types.Chunk, error) { return types.Chunk{}, nil }
+func (s *recordingStore) ListFiles() ([]types.FileMeta, error)    { return nil, nil }
+func (s *recordingStore) DeleteFile(string) error                 { return nil }
+func (s *recordingStore) Close() error                            { return nil }
+
+func zipFile(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestIndexer(ms store.MetadataStore) *indexer {
+	sentences := chunker.NewSentenceChunker(50)
+	return &indexer{
+		readers: reader.NewDefaultRegistry(),
+		embedChain: &chain.EmbedChain{
+			EmbedFunc: func(string) ([]float32, error) { return []float32{1, 0}, nil },
+			MetaStore: ms,
+			VectorDB:  store.NewMemoryVectorStore(),
+		},
+		chunker:          sentences,
+		rowChunker:       chunker.NewRowChunker(1, 200),
+		semanticChunker:  sentences,
+		parentChunker:    sentences,
+		codeChunker:      chunker.NewCodeChunker(80),
+		structureChunker: chunker.NewStructureChunker(sentences),
+	}
+}
+
+func TestIndexArchiveMembers(t *testing.T) {
+	inner := zipFile(t, "notes.txt", "Inner notes.")
+	archive := zipFile(t,
+		"data/people.csv", "Name,Age\nAnn,31\nBob,40\n",
+		"guide.md", "# Guide\n\n## Install\n\nRun it.\n",
+		"main.go", "package main\n\nfunc main() {}\n",
+		"nested/inner.zip", string(inner),
+		"broken.pdf", "not a pdf",
+	)
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	if err := os.WriteFile(path, archive, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ms := &recordingStore{chunks: map[string][]types.Chunk{}}
+	if _, err := newTestIndexer(ms).index(context.Background(), "bundle", path, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		doc    string
+		chunks int
+		check  func(types.Chunk) bool
+	}{
+		{"bundle!data/people.csv", 2, func(c types.Chunk) bool {
+			return strings.HasPrefix(c.Text, "Name: ") && c.Meta["path"] == "data/people.csv" && c.Meta["records"] != ""
+		}},
+		{"bundle!guide.md", 1, func(c types.Chunk) bool { return strings.Join(c.Headings, "/") == "Guide/Install" }},
+		{"bundle!main.go", 2, func(c types.Chunk) bool { return c.Meta["symbol"] != "" }},
+		{"bundle!nested/inner.zip!notes.txt", 1, func(c types.Chunk) bool { return c.Meta["path"] == "nested/inner.zip!notes.txt" }},
+	}
+	for _, tt := range tests {
+		chunks := ms.chunks[tt.doc]
+		if len(chunks) != tt.chunks {
+			t.Errorf("%s: got %d chunks, want %d", tt.doc, len(chunks), tt.chunks)
+			continue
+		}
+		for _, c := range chunks {
+			if !tt.check(c) {
+				t.Errorf("%s: unexpected chunk %+v", tt.doc, c)
+			}
+		}
+	}
+	if _, ok := ms.chunks["bundle!broken.pdf"]; ok {
+		t.Error("unreadable member was indexed")
+	}
+}
+
+func TestIndexExtractError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.zip")
+	if err := os.WriteFile(path, []byte("PK\x03\x04 truncated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ms := &recordingStore{chunks: map[string][]types.Chunk{}}
+	_, err := newTestIndexer(ms).index(context.Background(), "broken", path, nil)
+	if !errors.Is(err, errExtract) {
+		t.Errorf("got %v, want errExtract", err)
+	}
+}
+
+func TestIndexKeepsNoState(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n\nfunc A() {}\n\nfunc B() {}\n",
+		"tool.py":   "def a():\n    pass\n\ndef b():\n    pass\n",
+		"notes.txt": "Plain notes.",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ms := &recordingStore{chunks: map[string][]types.Chunk{}}
+	ix := newTestIndexer(ms)
+	chunkerBefore := ix.embedChain.Chunker
+
+	// Files indexed at the same time must not see each other's settings.
+	var wg sync.WaitGroup
+	errs := make(chan error, len(files))
+	for name := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ix.index(context.Background(), name, filepath.Join(dir, name), map[string]string{"file": name})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ix.embedChain.DocName != "" || ix.embedChain.Meta != nil || ix.embedChain.Chunker != chunkerBefore || ix.codeChunker.Language != "" {
+		t.Errorf("indexer state changed: chain %+v, code language %q", ix.embedChain, ix.codeChunker.Language)
+	}
+	tests := []struct {
+		doc     string
+		symbols []string
+	}{
+		{"main.go", []string{"main", "A", "B"}},
+		{"tool.py", []string{"a", "b"}},
+		{"notes.txt", []string{""}},
+	}
+	for _, tt := range tests {
+		var symbols []string
+		for _, c := range ms.chunks[tt.doc] {
+			symbols = append(symbols, c.Meta["symbol"])
+			if c.Meta["file"] != tt.doc {
+				t.Errorf("%s: chunk has file meta %q", tt.doc, c.Meta["file"])
+			}
+		}
+		if strings.Join(symbols, ",") != strings.Join(tt.symbols, ",") {
+			t.Errorf("%s: symbols %q, want %q", tt.doc, symbols, tt.symbols)
+		}
+	}
+}
@@ -3,54 +3,107 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
-	"path/filepath"
+	"time"
 	"github.com/Ashank007/docai/chain"
 	"github.com/Ashank007/docai/chunker"
 	"github.com/Ashank007/docai/embedder"
-	"github.com/Ashank007/docai/generator"
 	"github.com/Ashank007/docai/reader"
 	"github.com/Ashank007/docai/retriever"
 	"github.com/Ashank007/docai/store"
-	"github.com/Ashank007/docai/summarizer" // New import for the summarizer package
+	"github.com/Ashank007/docai/summarizer"
+	"github.com/Ashank007/docai/tokenizer"
 )
 
 func main() {
-	// STEP 1: Init all components
-	// Chunkers, Embedders, Generators, Readers (No Change)
-	ch := chunker.NewSentenceChunker(200)
-	embed := embedder.NewOllama("nomic-embed-text", "http://localhost:11434/api/embeddings")
-	gen := generator.NewOllama("llama3.1", "http://localhost:11434/api/generate")
+	// "docai cache ..." inspects and trims the embedding cache instead of running the CLI.
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		if err := runCacheCommand(dbPath, os.Args[2:]); err != nil {
+			log.Fatal("❌ ", err)
+		}
+		return
+	}
+	// "docai summarize <file|->" summarizes one document, e.g. piped to stdin, and exits.
+	if len(os.Args) > 1 && os.Args[1] == "summarize" {
+		if err := runSummarizeCommand(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			log.Fatal("❌ ", err)
+		}
+		return
+	}
 
-	pdfReader := reader.NewPDFReader()
-	textReader := reader.NewTextReader()
-	docxReader := reader.NewDocxReader() // Ensure this uses your robust manual parser
+	// STEP 1: Init the chunkers, backend, reader registry and stores
+	// Chunks are sized in tokens to fit the embedding model's context. Point
+	// DOCAI_VOCAB at a tiktoken vocab file for exact counts.
+	tok, err := tokenizer.Load(os.Getenv("DOCAI_VOCAB"))
+	if err != nil {
+		log.Printf("⚠️ %v. Falling back to approximate token counts.\n", err)
+	}
+	ch := chunker.NewTokenChunker(tok, 256, 48) // 48 tokens of overlap between neighbouring chunks
+	rowChunker := chunker.NewRowChunker(10, 200)
+	codeChunker := chunker.NewCodeChunker(80)
+	// Ollama by default, or an OpenAI-compatible server (see backend.go).
+	embed, gen, cacheKey := newBackend()
+	// Long unstructured text is split where the topic changes.
+	semanticChunker := chunker.NewSemanticChunker(embed, 256)
+	semanticChunker.Tokenizer = tok
+	// Structured documents are chunked section by section under their headings.
+	structureChunker := chunker.NewStructureChunker(ch)
+	// Other documents are searched by small chunks and answered from the larger passage around them.
+	parentChunker := chunker.NewParentChildChunker(chunker.NewTokenChunker(tok, 768, 0), chunker.NewTokenChunker(tok, 128, 24))
+
+	readers := reader.NewDefaultRegistry()
 
 	meta := store.NewSQLiteStore()
-	if err := meta.Init("test.db"); err != nil {
+	if err := meta.Init(dbPath); err != nil {
 		log.Fatal("❌ SQLite MetadataStore init failed:", err)
 	}
 	defer meta.Close()
 
+	// Text that was embedded before, e.g. on the previous start, is answered
+	// from the embedding cache in the same database.
+	embedCache, err := store.NewSQLiteEmbeddingCache(meta.DB())
+	if err != nil {
+		log.Fatal("❌ Embedding cache init failed:", err)
+	}
+	cachedEmbed := embedder.NewCachedEmbedder(embed, embedCache, cacheKey)
+	semanticChunker.Embedder = cachedEmbed
+
 	vector := store.NewMemoryVectorStore()
 
-	retr := retriever.NewCosineRetriever(vector, meta, embed.Embed)
+	retr := retriever.NewCosineRetriever(vector, meta, cachedEmbed.Embed)
+	retr.ExpandParents = true
+	retr.EmbedContextFunc = cachedEmbed.EmbedContext
 
 	actualEmbedChain := &chain.EmbedChain{
 		DocName:   "",
 		Chunker:   ch,
-		EmbedFunc: embed.Embed,
+		EmbedFunc: cachedEmbed.Embed,
 		MetaStore: meta,
 		VectorDB:  vector,
+		// Chunks are embedded 64 at a time through the backend's batch endpoint,
+		// with up to 4 batches in flight.
+		EmbedBatchContextFunc: cachedEmbed.EmbedBatchContext,
+		BatchSize:             64,
+		Workers:               4,
+		Progress: func(done, total int) {
+			fmt.Printf("\r  embedded %d/%d chunks", done, total)
+			if done == total {
+				fmt.Println()
+			}
+		},
 	}
 
 	actualQueryChain := &chain.QueryChain{
-		EmbedFunc: embed.Embed,
-		Retriever: retr,
-		Generator: gen.Generate,
+		EmbedFunc:        cachedEmbed.Embed,
+		Retriever:        retr,
+		Generator:        gen.Generate,
+		GeneratorContext: gen.GenerateContext,
 	}
 
 	chainBuilder := chain.NewChainBuilder().
@@ -61,7 +114,8 @@ func main() {
 	queryChain := chainBuilder.BuildQuery()
 
 	// Initialize the new Summarizer library component
-	docSummarizer := summarizer.NewSummarizer(ch, gen, pdfReader, textReader, docxReader)
+	docSummarizer := summarizer.NewSummarizerWithRegistry(ch, gen, readers)
+	docSummarizer.Tokenizer = tok
 
 	// ---
 
@@ -74,37 +128,42 @@ func main() {
 		"notes_data":        "./testdata/notes.txt",
 	}
 
+	ix := &indexer{
+		readers:          readers,
+		embedChain:       embedChain,
+		chunker:          ch,
+		rowChunker:       rowChunker,
+		semanticChunker:  semanticChunker,
+		parentChunker:    parentChunker,
+		codeChunker:      codeChunker,
+		structureChunker: structureChunker,
+	}
+
+	// Indexing stops on Ctrl-C or when it takes longer than the deadline.
+	ingestCtx, stopIngest := operationContext(ingestTimeout)
 	for docName, filePath := range documentsToProcess {
 		fmt.Printf("\nProcessing document for query indexing: %s (%s)\n", docName, filePath)
-		
-		var currentReader reader.Reader // We need to determine reader type here too for initial embedding
-		fileExtension := strings.ToLower(filepath.Ext(filePath))
-
-		switch fileExtension {
-		case ".pdf":
-			currentReader = pdfReader
-		case ".txt":
-			currentReader = textReader
-		case ".docx":
-			currentReader = docxReader
-		default:
-			log.Printf("⚠️ Unsupported file type for %s: %s. Skipping for query indexing.\n", filePath, fileExtension)
+
+		pageCount, err := ix.index(ingestCtx, docName, filePath, nil)
+		if errors.Is(err, errExtract) {
+			log.Printf("⚠️ %v. Skipping for query indexing.\n", err)
 			continue
 		}
-
-		text, err := currentReader.Extract(filePath)
 		if err != nil {
-			log.Printf("⚠️ File extract failed for %s: %v. Skipping for query indexing.\n", docName, err)
-			continue
+			log.Fatalf("❌ %v", err)
 		}
-
-		embedChain.DocName = docName
-		_, err = embedChain.Run(text)
+		rd, err := readers.Lookup(filePath)
 		if err != nil {
-			log.Fatalf("❌ EmbedChain failed for %s: %v", docName, err)
+			log.Printf("⚠️ %v. Not recording file info for %s.\n", err, docName)
+			continue
+		}
+		if err := chain.SaveFile(meta, rd, docName, filePath, pageCount); err != nil {
+			log.Printf("⚠️ %v\n", err)
 		}
-		fmt.Printf("✅ Document '%s' indexed for querying successfully.\n", docName)
 	}
+	stopIngest()
+	counts := cachedEmbed.Counts()
+	fmt.Printf("\nEmbedding cache: %d hits, %d misses (%.0f%% hit rate)\n", counts.Hits, counts.Misses, 100*counts.HitRate())
 
 	// ---
 
@@ -137,7 +196,9 @@ func main() {
 
 			fmt.Printf("\nSearching for: '%s' in document: '%s' (empty means all)\n", query, docNameFilter)
 
-			answer, err := queryChain.Run(query, docNameFilter)
+			ctx, cancel := operationContext(queryTimeout)
+			answer, err := queryChain.RunContext(ctx, query, docNameFilter)
+			cancel()
 			if err != nil {
 				log.Printf("❌ QueryChain failed: %v", err) // Use log.Printf instead of log.Fatalf here for graceful error handling
 			} else {
@@ -156,7 +217,9 @@ func main() {
 
 			fmt.Printf("\nSummarizing document: '%s'\n", filePathToSummarize)
 
-			summary, err := docSummarizer.SummarizeDocument(filePathToSummarize)
+			ctx, cancel := operationContext(summarizeTimeout)
+			summary, err := docSummarizer.SummarizeDocumentContext(ctx, filePathToSummarize)
+			cancel()
 			if err != nil {
 				log.Printf("❌ Summarization failed for '%s': %v", filePathToSummarize, err)
 			} else {
@@ -169,3 +232,24 @@ func main() {
 	}
 }
 
+// dbPath is the SQLite database holding chunks, documents and the embedding cache.
+const dbPath = "test.db"
+
+// Deadlines for the model calls of one CLI operation, so that a hung model
+// cannot block the CLI forever.
+const (
+	ingestTimeout    = 30 * time.Minute
+	queryTimeout     = 5 * time.Minute
+	summarizeTimeout = 30 * time.Minute
+)
+
+// operationContext returns a context for one CLI operation that is cancelled
+// by Ctrl-C or after timeout. Once it is cancelled, Ctrl-C quits again.
+func operationContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
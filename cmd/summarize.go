@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/summarizer"
+	"github.com/Ashank007/docai/tokenizer"
+)
+
+const summarizeUsage = `usage: docai summarize [-name notes.md] <file|->
+
+Summarizes a file, or the document piped to stdin when the path is "-".
+-name gives stdin content a file name, whose extension picks the reader
+when the content is not recognised.`
+
+// runSummarizeCommand runs "docai summarize", printing the summary to stdout.
+func runSummarizeCommand(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("summarize", flag.ContinueOnError)
+	name := fs.String("name", "stdin", "file name of the content read from stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("summarize needs one file, or - for stdin\n%s", summarizeUsage)
+	}
+
+	tok, err := tokenizer.Load(os.Getenv("DOCAI_VOCAB"))
+	if err != nil {
+		log.Printf("⚠️ %v. Falling back to approximate token counts.\n", err)
+	}
+	_, gen, _ := newBackend()
+	// The summarizer streams nothing unless given an Output, so only the
+	// finished summary reaches stdout and it can be piped.
+	s := summarizer.NewSummarizerWithRegistry(chunker.NewTokenChunker(tok, 256, 48), gen, reader.NewDefaultRegistry())
+	s.Tokenizer = tok
+
+	ctx, cancel := operationContext(summarizeTimeout)
+	defer cancel()
+	summary, err := summarizeInput(ctx, s, fs.Arg(0), *name, stdin)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, summary)
+	return nil
+}
+
+// summarizeInput summarizes the file at path, or stdin when path is "-".
+func summarizeInput(ctx context.Context, s *summarizer.Summarizer, path, name string, stdin io.Reader) (string, error) {
+	if path == "-" {
+		return s.SummarizeFromContext(ctx, stdin, name)
+	}
+	return s.SummarizeDocumentContext(ctx, path)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/summarizer"
+)
+
+// echoGenerator answers with the contexts it is given.
+type echoGenerator struct{}
+
+func (echoGenerator) Generate(query string, contexts []string) (string, error) {
+	return strings.Join(contexts, " | "), nil
+}
+
+func (echoGenerator) Name() string { return "echo" }
+
+func TestSummarizeInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("From a file."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		path  string
+		stdin string
+		want  string
+	}{
+		{"file", path, "", "From a file."},
+		{"stdin", "-", "<html><body><p>From stdin.</p></body></html>", "From stdin."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := summarizer.NewSummarizerWithRegistry(chunker.NewSentenceChunker(50), echoGenerator{}, reader.NewDefaultRegistry())
+			got, err := summarizeInput(context.Background(), s, tt.path, "stdin", strings.NewReader(tt.stdin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSummarizeCommandUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"a.txt", "b.txt"}} {
+		if err := runSummarizeCommand(args, strings.NewReader(""), os.Stdout); err == nil {
+			t.Errorf("runSummarizeCommand(%q) succeeded, want a usage error", args)
+		}
+	}
+}
+
+func TestRunSummarizeCommandOutput(t *testing.T) {
+	// The backend streams "A short summary." token by token.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"A short", " summary."} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+	t.Setenv("DOCAI_OPENAI_BASE_URL", srv.URL+"/v1")
+
+	// Capture what the generator would echo to the terminal.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	var stdout bytes.Buffer
+	runErr := runSummarizeCommand([]string{"-name", "notes.txt", "-"}, strings.NewReader("Some notes to summarize."), &stdout)
+	os.Stdout = saved
+	w.Close()
+	echoed, _ := io.ReadAll(r)
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+
+	if got := stdout.String(); got != "A short summary.\n" {
+		t.Errorf("stdout = %q, want the summary once", got)
+	}
+	if len(echoed) > 0 {
+		t.Errorf("streamed %q to the terminal", echoed)
+	}
+}
@@ -0,0 +1,142 @@
+package embedder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Cache stores embeddings by a key naming the embedding model, see CacheKey,
+// and the hex-encoded SHA-256 of the embedded text, e.g. store.SQLiteEmbeddingCache.
+type Cache interface {
+	Get(key, hash string) ([]float32, bool, error)
+	Put(key, hash string, vec []float32) error
+}
+
+// CacheKey returns the cache key of the vectors a model produces, e.g.
+// "openai:text-embedding-3-small/256@https://api.openai.com/v1". The same
+// model name on another server, or shortened to other dimensions, gives
+// other vectors and so another key. dimensions is 0 for the model's own size.
+func CacheKey(backend, baseURL, model string, dimensions int) string {
+	key := backend + ":" + model
+	if dimensions > 0 {
+		key += fmt.Sprintf("/%d", dimensions)
+	}
+	return key + "@" + strings.TrimSuffix(baseURL, "/")
+}
+
+// CachedEmbedder wraps an Embedder and answers texts it has embedded before
+// from Cache. Vectors are cached under Key, so switching models, servers or
+// dimensions never returns vectors of the old ones.
+type CachedEmbedder struct {
+	Embedder Embedder
+	Cache    Cache
+	Key      string
+
+	hits, misses atomic.Int64
+}
+
+// CacheCounts holds the lookups a CachedEmbedder has made.
+type CacheCounts struct {
+	Hits, Misses int64
+}
+
+// HitRate returns the share of lookups answered from the cache, from 0 to 1.
+func (c CacheCounts) HitRate() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// NewCachedEmbedder creates a CachedEmbedder that caches the vectors of e
+// under key, e.g. OllamaEmbedder.CacheKey().
+func NewCachedEmbedder(e Embedder, cache Cache, key string) *CachedEmbedder {
+	return &CachedEmbedder{Embedder: e, Cache: cache, Key: key}
+}
+
+func (c *CachedEmbedder) Embed(text string) ([]float32, error) {
+	return c.EmbedContext(context.Background(), text)
+}
+
+// EmbedContext returns the cached vector of text, or embeds and caches it.
+func (c *CachedEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := c.EmbedBatchContext(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (c *CachedEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	return c.EmbedBatchContext(context.Background(), texts)
+}
+
+// EmbedBatchContext answers the texts it can from the cache and embeds the
+// rest in one batch with the wrapped Embedder.
+func (c *CachedEmbedder) EmbedBatchContext(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	hashes := make([]string, len(texts))
+	missing := make(map[string][]int) // texts to embed by hash, each embedded once
+	var order []string
+	for i, text := range texts {
+		sum := sha256.Sum256([]byte(text))
+		hashes[i] = hex.EncodeToString(sum[:])
+		if _, seen := missing[hashes[i]]; seen {
+			missing[hashes[i]] = append(missing[hashes[i]], i)
+			continue
+		}
+		vec, ok, err := c.Cache.Get(c.Key, hashes[i])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			vectors[i] = vec
+			continue
+		}
+		missing[hashes[i]] = []int{i}
+		order = append(order, hashes[i])
+	}
+	misses := 0
+	for _, indexes := range missing {
+		misses += len(indexes)
+	}
+	c.hits.Add(int64(len(texts) - misses))
+	c.misses.Add(int64(misses))
+	if len(order) == 0 {
+		return vectors, nil
+	}
+
+	missingTexts := make([]string, len(order))
+	for j, hash := range order {
+		missingTexts[j] = texts[missing[hash][0]]
+	}
+	embedded, err := EmbedBatchContext(ctx, c.Embedder, missingTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(order) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(embedded), len(order))
+	}
+	for j, hash := range order {
+		for _, i := range missing[hash] {
+			vectors[i] = embedded[j]
+		}
+		if err := c.Cache.Put(c.Key, hash, embedded[j]); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// Counts returns the cache hits and misses since the CachedEmbedder was created.
+func (c *CachedEmbedder) Counts() CacheCounts {
+	return CacheCounts{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+func (c *CachedEmbedder) Name() string {
+	return "cached-" + c.Embedder.Name()
+}
@@ -0,0 +1,87 @@
+package embedder
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mapCache is an in-memory Cache.
+type mapCache map[string][]float32
+
+func (c mapCache) Get(key, hash string) ([]float32, bool, error) {
+	vec, ok := c[key+" "+hash]
+	return vec, ok, nil
+}
+
+func (c mapCache) Put(key, hash string, vec []float32) error {
+	c[key+" "+hash] = vec
+	return nil
+}
+
+// countingEmbedder embeds a text as [len(text), scale] and counts the texts it embeds.
+type countingEmbedder struct {
+	scale float32
+	texts int
+}
+
+func (e *countingEmbedder) Embed(text string) ([]float32, error) {
+	e.texts++
+	return []float32{float32(len(text)), e.scale}, nil
+}
+func (e *countingEmbedder) Name() string { return "counting" }
+
+func TestCacheKey(t *testing.T) {
+	withDims := NewOpenAI("text-embedding-3-small", "https://api.openai.com/v1/", "")
+	withDims.Dimensions = 256
+	tests := []struct {
+		name, got, want string
+	}{
+		{"ollama", NewOllama("nomic-embed-text", "http://localhost:11434/api/embeddings").CacheKey(), "ollama:nomic-embed-text@http://localhost:11434"},
+		{"ollama other server", NewOllama("nomic-embed-text", "http://gpu:11434/api/embeddings").CacheKey(), "ollama:nomic-embed-text@http://gpu:11434"},
+		{"openai", NewOpenAI("text-embedding-3-small", "https://api.openai.com/v1", "").CacheKey(), "openai:text-embedding-3-small@https://api.openai.com/v1"},
+		{"openai dimensions", withDims.CacheKey(), "openai:text-embedding-3-small/256@https://api.openai.com/v1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: CacheKey() = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCachedEmbedder(t *testing.T) {
+	cache := mapCache{}
+	first := &countingEmbedder{scale: 1}
+	c := NewCachedEmbedder(first, cache, "a")
+
+	got, err := c.EmbedBatch([]string{"one", "two", "one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float32{{3, 1}, {3, 1}, {3, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if first.texts != 2 {
+		t.Errorf("embedded %d texts, want 2 for the repeated text", first.texts)
+	}
+	if _, err := c.Embed("two"); err != nil {
+		t.Fatal(err)
+	}
+	if first.texts != 2 {
+		t.Errorf("embedded a cached text again")
+	}
+	counts := c.Counts()
+	if counts.Hits != 1 || counts.Misses != 3 || counts.HitRate() != 0.25 {
+		t.Errorf("counts = %+v with hit rate %v, want 1 hit and 3 misses", counts, counts.HitRate())
+	}
+
+	// Another key, e.g. the same model shortened to other dimensions, does
+	// not see the vectors cached under the first.
+	second := &countingEmbedder{scale: 2}
+	vec, err := NewCachedEmbedder(second, cache, "b").Embed("one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.texts != 1 || !reflect.DeepEqual(vec, []float32{3, 2}) {
+		t.Errorf("got %v after %d embeddings, want a fresh vector", vec, second.texts)
+	}
+}
@@ -1,6 +1,75 @@
 package embedder
 
+import "context"
+
 type Embedder interface {
 	Embed(text string) ([]float32, error)
 	Name() string
 }
+
+// BatchEmbedder is implemented by embedders that can embed several texts in
+// one request. The vectors are returned in the order of the texts.
+type BatchEmbedder interface {
+	Embedder
+	EmbedBatch(texts []string) ([][]float32, error)
+}
+
+// ContextEmbedder is implemented by embedders whose requests can be bound to
+// a context, so that a caller can set a deadline or cancel them.
+type ContextEmbedder interface {
+	Embedder
+	EmbedContext(ctx context.Context, text string) ([]float32, error)
+}
+
+// ContextBatchEmbedder is a BatchEmbedder whose requests can be bound to a context.
+type ContextBatchEmbedder interface {
+	BatchEmbedder
+	EmbedBatchContext(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedBatch embeds texts with e in one call when it is a BatchEmbedder, and
+// one text at a time otherwise.
+func EmbedBatch(e Embedder, texts []string) ([][]float32, error) {
+	return EmbedBatchContext(context.Background(), e, texts)
+}
+
+// EmbedContext embeds text with e, bound to ctx when e is a ContextEmbedder.
+// Other embedders are not called once ctx is done.
+func EmbedContext(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	if ce, ok := e.(ContextEmbedder); ok {
+		return ce.EmbedContext(ctx, text)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.Embed(text)
+}
+
+// EmbedBatchContext is EmbedBatch bound to ctx, using the most capable
+// method e has.
+func EmbedBatchContext(ctx context.Context, e Embedder, texts []string) ([][]float32, error) {
+	switch be := e.(type) {
+	case ContextBatchEmbedder:
+		return be.EmbedBatchContext(ctx, texts)
+	case BatchEmbedder:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return be.EmbedBatch(texts)
+	}
+	return embedEach(ctx, func(ctx context.Context, text string) ([]float32, error) {
+		return EmbedContext(ctx, e, text)
+	}, texts)
+}
+
+func embedEach(ctx context.Context, embed func(context.Context, string) ([]float32, error), texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec, err := embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
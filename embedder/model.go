@@ -2,15 +2,28 @@ package embedder
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// defaultClient sends the requests of embedders without a Client. Unlike
+// http.DefaultClient it gives up on a server that stops answering.
+var defaultClient = &http.Client{Timeout: 2 * time.Minute}
+
 type OllamaEmbedder struct {
-	Model string
-	URL   string // e.g., http://localhost:11434/api/embeddings
+	Model    string
+	URL      string       // e.g., http://localhost:11434/api/embeddings
+	BatchURL string       // e.g., http://localhost:11434/api/embed; empty embeds batches one text at a time
+	Client   *http.Client // nil uses a client with a default timeout
+
+	batchUnsupported atomic.Bool // set once the server turns out to lack BatchURL
 }
 
 type embedRequest struct {
@@ -22,15 +35,38 @@ type embedResponse struct {
 	Embedding []float32 `json:"embedding"`
 }
 
-// NewOllama returns an Embedder for Ollama's embedding API
+type embedBatchRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embedBatchResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewOllama returns an Embedder for Ollama's embedding API. When url is the
+// /api/embeddings endpoint, batches go to /api/embed on the same server.
 func NewOllama(model, url string) *OllamaEmbedder {
-	return &OllamaEmbedder{
+	o := &OllamaEmbedder{
 		Model: model,
 		URL:   url,
 	}
+	if base, ok := strings.CutSuffix(url, "/api/embeddings"); ok {
+		o.BatchURL = base + "/api/embed"
+	}
+	return o
 }
 
 func (o *OllamaEmbedder) Embed(text string) ([]float32, error) {
+	return o.EmbedContext(context.Background(), text)
+}
+
+// EmbedContext is Embed with the HTTP request bound to ctx.
+func (o *OllamaEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
 	reqBody := embedRequest{
 		Model:  o.Model,
 		Prompt: text,
@@ -40,7 +76,7 @@ func (o *OllamaEmbedder) Embed(text string) ([]float32, error) {
 		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
 	}
 
-	resp, err := http.Post(o.URL, "application/json", bytes.NewReader(data))
+	resp, err := o.post(ctx, o.URL, data)
 	if err != nil {
 		return nil, fmt.Errorf("embedding request failed: %w", err)
 	}
@@ -59,6 +95,77 @@ func (o *OllamaEmbedder) Embed(text string) ([]float32, error) {
 	return result.Embedding, nil
 }
 
+// EmbedBatch embeds texts with one request to BatchURL. Servers older than
+// the /api/embed endpoint answer 404, after which texts are embedded one at
+// a time with Embed. A 404 that reports an unknown model is returned as an
+// error instead, and batching is tried again on the next call.
+func (o *OllamaEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	return o.EmbedBatchContext(context.Background(), texts)
+}
+
+// EmbedBatchContext is EmbedBatch with the HTTP requests bound to ctx.
+func (o *OllamaEmbedder) EmbedBatchContext(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	if o.BatchURL == "" || o.batchUnsupported.Load() {
+		return embedEach(ctx, o.EmbedContext, texts)
+	}
+
+	data, err := json.Marshal(embedBatchRequest{Model: o.Model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	resp, err := o.post(ctx, o.BatchURL, data)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		// Ollama also answers 404 for a model that has not been pulled.
+		var body errorResponse
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body) == nil && strings.Contains(strings.ToLower(body.Error), "model") {
+			return nil, fmt.Errorf("embedding request failed with status: %s: %s", resp.Status, body.Error)
+		}
+		o.batchUnsupported.Store(true)
+		return embedEach(ctx, o.EmbedContext, texts)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed with status: %s", resp.Status)
+	}
+
+	var result embedBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.New("failed to decode embedding response")
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d texts", len(result.Embeddings), len(texts))
+	}
+	return result.Embeddings, nil
+}
+
+// post sends a JSON request body to url.
+func (o *OllamaEmbedder) post(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := o.Client
+	if client == nil {
+		client = defaultClient
+	}
+	return client.Do(req)
+}
+
+// CacheKey returns the key of the embedder's vectors in a Cache.
+func (o *OllamaEmbedder) CacheKey() string {
+	base, _ := strings.CutSuffix(o.URL, "/api/embeddings")
+	return CacheKey("ollama", base, o.Model, 0)
+}
+
 func (o *OllamaEmbedder) Name() string {
 	return "ollama-embedder"
 }
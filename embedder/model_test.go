@@ -0,0 +1,201 @@
+package embedder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ollamaServer fakes Ollama's embedding API. A text is embedded as
+// [len(text), 1]. batch is the status /api/embed answers with, and vectors,
+// when not negative, the number of vectors it returns.
+func ollamaServer(t *testing.T, batch, vectors int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/embeddings":
+			var req embedRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(embedResponse{Embedding: []float32{float32(len(req.Prompt)), 1}})
+		case "/api/embed":
+			if batch != http.StatusOK {
+				w.WriteHeader(batch)
+				return
+			}
+			var req embedBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var resp embedBatchResponse
+			for _, text := range req.Input {
+				resp.Embeddings = append(resp.Embeddings, []float32{float32(len(text)), 1})
+			}
+			if vectors >= 0 {
+				resp.Embeddings = resp.Embeddings[:vectors]
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestOllamaEmbedBatch(t *testing.T) {
+	texts := []string{"a", "bb", "ccc"}
+	want := [][]float32{{1, 1}, {2, 1}, {3, 1}}
+	tests := []struct {
+		name    string
+		status  int
+		vectors int
+		noBatch bool
+		calls   []string // paths requested by two EmbedBatch calls
+		wantErr string
+	}{
+		{
+			name:   "batch endpoint",
+			status: http.StatusOK, vectors: -1,
+			calls: []string{"/api/embed", "/api/embed"},
+		},
+		{
+			name:   "old server falls back once",
+			status: http.StatusNotFound, vectors: -1,
+			calls: []string{"/api/embed", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings"},
+		},
+		{
+			name:   "method not allowed falls back",
+			status: http.StatusMethodNotAllowed, vectors: -1,
+			calls: []string{"/api/embed", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings"},
+		},
+		{
+			name:    "no batch URL",
+			noBatch: true, vectors: -1,
+			calls: []string{"/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings", "/api/embeddings"},
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError, vectors: -1,
+			wantErr: "500",
+		},
+		{
+			name:   "missing vectors",
+			status: http.StatusOK, vectors: 2,
+			wantErr: "2 vectors for 3 texts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := ollamaServer(t, tt.status, tt.vectors)
+			o := NewOllama("test-model", srv.URL+"/api/embeddings")
+			if tt.noBatch {
+				o.BatchURL = ""
+			}
+			for range 2 {
+				got, err := o.EmbedBatch(texts)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("got error %v, want %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got %v, want %v", got, want)
+				}
+			}
+			if !reflect.DeepEqual(*calls, tt.calls) {
+				t.Errorf("requested %v, want %v", *calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestOllamaEmbedBatchUnknownModel(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: `model "nope" not found, try pulling it first`})
+	}))
+	defer srv.Close()
+
+	o := NewOllama("nope", srv.URL+"/api/embeddings")
+	for range 2 {
+		if _, err := o.EmbedBatch([]string{"a", "b"}); err == nil || !strings.Contains(err.Error(), "not found, try pulling it first") {
+			t.Fatalf("got error %v, want the model error", err)
+		}
+	}
+	// The batch endpoint is not given up on, and texts are not embedded one by one.
+	if want := []string{"/api/embed", "/api/embed"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("requested %v, want %v", calls, want)
+	}
+}
+
+func TestNewOllamaBatchURL(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost:11434/api/embeddings", "http://localhost:11434/api/embed"},
+		{"http://proxy/embed", ""},
+	}
+	for _, tt := range tests {
+		if got := NewOllama("m", tt.url).BatchURL; got != tt.want {
+			t.Errorf("NewOllama(%q).BatchURL = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultClientTimeout(t *testing.T) {
+	if defaultClient.Timeout <= 0 {
+		t.Fatalf("default client has no timeout")
+	}
+	// A server that never answers must not hang an embedder without a Client.
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer srv.Close()
+	defer close(hang)
+	saved := defaultClient
+	defaultClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { defaultClient = saved }()
+
+	tests := []struct {
+		name  string
+		embed BatchEmbedder
+	}{
+		{"ollama", NewOllama("m", srv.URL+"/api/embeddings")},
+		{"openai", NewOpenAI("m", srv.URL+"/v1", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if _, err := tt.embed.EmbedBatch([]string{"a"}); err == nil {
+				t.Error("got no error from a server that never answers")
+			}
+			if d := time.Since(start); d > 2*time.Second {
+				t.Errorf("gave up after %v", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// OpenAIEmbedder is an Embedder for servers that implement the OpenAI
+// /v1/embeddings API, such as llama.cpp server, vLLM, LocalAI or a hosted
+// endpoint.
+type OpenAIEmbedder struct {
+	Model   string
+	BaseURL string // API root including the version, e.g. http://localhost:8080/v1
+	APIKey  string // optional
+	// APIKeyHeader names the header that carries APIKey. Empty sends
+	// "Authorization: Bearer <key>"; any other header gets the key as is,
+	// e.g. "api-key" for Azure OpenAI.
+	APIKeyHeader string
+	Dimensions   int          // optional: shortens vectors on models that support it
+	Client       *http.Client // nil uses a client with a default timeout
+}
+
+type openAIEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// NewOpenAI returns an Embedder for the OpenAI-compatible API at baseURL.
+// apiKey may be empty for local servers.
+func NewOpenAI(model, baseURL, apiKey string) *OpenAIEmbedder {
+	return &OpenAIEmbedder{
+		Model:   model,
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+	}
+}
+
+func (o *OpenAIEmbedder) Embed(text string) ([]float32, error) {
+	return o.EmbedContext(context.Background(), text)
+}
+
+// EmbedContext is Embed with the HTTP request bound to ctx.
+func (o *OpenAIEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := o.EmbedBatchContext(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (o *OpenAIEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	return o.EmbedBatchContext(context.Background(), texts)
+}
+
+// EmbedBatchContext embeds texts with one request to /embeddings.
+func (o *OpenAIEmbedder) EmbedBatchContext(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(openAIEmbedRequest{Model: o.Model, Input: texts, Dimensions: o.Dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	url := strings.TrimSuffix(o.BaseURL, "/") + "/embeddings"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		if o.APIKeyHeader == "" {
+			req.Header.Set("Authorization", "Bearer "+o.APIKey)
+		} else {
+			req.Header.Set(o.APIKeyHeader, o.APIKey)
+		}
+	}
+	client := o.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed with status: %s%s", resp.Status, openAIErrorMessage(resp.Body))
+	}
+
+	var result openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d texts", len(result.Data), len(texts))
+	}
+	// The API tags every vector with the position of its input.
+	sort.Slice(result.Data, func(i, j int) bool { return result.Data[i].Index < result.Data[j].Index })
+	vectors := make([][]float32, len(texts))
+	for i, d := range result.Data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// CacheKey returns the key of the embedder's vectors in a Cache. Dimensions
+// is part of it, since it changes the vectors the model returns.
+func (o *OpenAIEmbedder) CacheKey() string {
+	return CacheKey("openai", o.BaseURL, o.Model, o.Dimensions)
+}
+
+func (o *OpenAIEmbedder) Name() string {
+	return "openai-embedder"
+}
+
+// openAIErrorMessage returns ": <message>" from an OpenAI error body, or ""
+// when the body holds none.
+func openAIErrorMessage(body io.Reader) string {
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<16)).Decode(&e); err != nil || e.Error.Message == "" {
+		return ""
+	}
+	return ": " + e.Error.Message
+}
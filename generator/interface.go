@@ -1,7 +1,48 @@
 package generator
 
+import (
+	"context"
+	"io"
+)
+
 // Generator defines the interface for any text generation model
 type Generator interface {
 	Generate(query string, contexts []string) (string, error)
 	Name() string
 }
+
+// ContextGenerator is implemented by generators whose requests can be bound
+// to a context, so that a caller can set a deadline or cancel a long answer.
+type ContextGenerator interface {
+	Generator
+	GenerateContext(ctx context.Context, query string, contexts []string) (string, error)
+}
+
+// GenerateContext generates with g, bound to ctx when g is a
+// ContextGenerator. Other generators are not called once ctx is done.
+func GenerateContext(ctx context.Context, g Generator, query string, contexts []string) (string, error) {
+	if cg, ok := g.(ContextGenerator); ok {
+		return cg.GenerateContext(ctx, query, contexts)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.Generate(query, contexts)
+}
+
+// StreamGenerator is implemented by generators that stream the answer as it
+// arrives, so that a caller can choose where it is written.
+type StreamGenerator interface {
+	ContextGenerator
+	GenerateStream(ctx context.Context, w io.Writer, query string, contexts []string) (string, error)
+}
+
+// GenerateStream generates with g, bound to ctx, and streams the answer to w
+// when g is a StreamGenerator. Pass io.Discard to collect the answer without
+// echoing it. Other generators write nothing to w.
+func GenerateStream(ctx context.Context, g Generator, w io.Writer, query string, contexts []string) (string, error) {
+	if sg, ok := g.(StreamGenerator); ok {
+		return sg.GenerateStream(ctx, w, query, contexts)
+	}
+	return GenerateContext(ctx, g, query, contexts)
+}
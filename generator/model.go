@@ -3,15 +3,26 @@ package generator
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 )
 
+// defaultClient sends the requests of generators without a Client. Unlike
+// http.DefaultClient it gives up on a server that stops answering, yet
+// leaves time for a long streamed answer.
+var defaultClient = &http.Client{Timeout: 10 * time.Minute}
+
 type OllamaGenerator struct {
-	Model string
-	URL   string // e.g., http://localhost:11434/api/generate
+	Model  string
+	URL    string       // e.g., http://localhost:11434/api/generate
+	Output io.Writer    // receives the answer as it streams; nil uses os.Stdout
+	Client *http.Client // nil uses a client with a default timeout
 }
 
 type genRequest struct {
@@ -34,6 +45,22 @@ func NewOllama(model, url string) *OllamaGenerator {
 
 // Generate sends the prompt to Ollama and prints the response as it streams
 func (g *OllamaGenerator) Generate(query string, contexts []string) (string, error) {
+	return g.GenerateContext(context.Background(), query, contexts)
+}
+
+// GenerateContext is Generate with the HTTP request bound to ctx. Cancelling
+// ctx stops the stream.
+func (g *OllamaGenerator) GenerateContext(ctx context.Context, query string, contexts []string) (string, error) {
+	out := g.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	return g.GenerateStream(ctx, out, query, contexts)
+}
+
+// GenerateStream is GenerateContext that streams the response to w in place
+// of Output.
+func (g *OllamaGenerator) GenerateStream(ctx context.Context, w io.Writer, query string, contexts []string) (string, error) {
 	prompt := g.constructPrompt(query, contexts)
 	reqBody := genRequest{
 		Model:  g.Model,
@@ -45,7 +72,16 @@ func (g *OllamaGenerator) Generate(query string, contexts []string) (string, err
 		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := http.Post(g.URL, "application/json", bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.URL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := g.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("generation request failed: %w", err)
 	}
@@ -64,7 +100,7 @@ func (g *OllamaGenerator) Generate(query string, contexts []string) (string, err
 		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
 			continue // Ignore malformed lines
 		}
-		fmt.Print(chunk.Response)                 // 🔥 Live terminal output
+		fmt.Fprint(w, chunk.Response)            // 🔥 Live terminal output
 		fullResponse.WriteString(chunk.Response) // Collect full response
 	}
 
@@ -77,6 +113,11 @@ func (g *OllamaGenerator) Generate(query string, contexts []string) (string, err
 
 // constructPrompt builds the final prompt from the context and question
 func (g *OllamaGenerator) constructPrompt(query string, contexts []string) string {
+	return buildPrompt(query, contexts)
+}
+
+// buildPrompt is the question-answering prompt shared by all generators.
+func buildPrompt(query string, contexts []string) string {
 	return fmt.Sprintf(`You are a helpful assistant AI. Use the following context to answer the question.
 
 Context:
@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// OpenAIGenerator is a Generator for servers that implement the OpenAI
+// /v1/chat/completions API, such as llama.cpp server, vLLM, LocalAI or a
+// hosted endpoint.
+type OpenAIGenerator struct {
+	Model   string
+	BaseURL string // API root including the version, e.g. http://localhost:8080/v1
+	APIKey  string // optional
+	// APIKeyHeader names the header that carries APIKey. Empty sends
+	// "Authorization: Bearer <key>"; any other header gets the key as is,
+	// e.g. "api-key" for Azure OpenAI.
+	APIKeyHeader string
+	MaxTokens    int // optional: caps the length of the answer
+	// Stream requests the answer as server-sent events and writes it to
+	// Output as it arrives, like OllamaGenerator does.
+	Stream bool
+	Output io.Writer    // nil uses os.Stdout
+	Client *http.Client // nil uses a client with a default timeout
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model     string        `json:"model"`
+	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		Delta        chatMessage `json:"delta"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewOpenAI returns a streaming Generator for the OpenAI-compatible API at
+// baseURL. apiKey may be empty for local servers.
+func NewOpenAI(model, baseURL, apiKey string) *OpenAIGenerator {
+	return &OpenAIGenerator{
+		Model:   model,
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Stream:  true,
+	}
+}
+
+func (g *OpenAIGenerator) Generate(query string, contexts []string) (string, error) {
+	return g.GenerateContext(context.Background(), query, contexts)
+}
+
+// GenerateContext sends the question and its context as a chat message with
+// the HTTP request bound to ctx. Cancelling ctx stops the stream.
+func (g *OpenAIGenerator) GenerateContext(ctx context.Context, query string, contexts []string) (string, error) {
+	out := g.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	return g.GenerateStream(ctx, out, query, contexts)
+}
+
+// GenerateStream is GenerateContext that writes a streamed answer to w in
+// place of Output.
+func (g *OpenAIGenerator) GenerateStream(ctx context.Context, w io.Writer, query string, contexts []string) (string, error) {
+	data, err := json.Marshal(chatRequest{
+		Model:     g.Model,
+		Messages:  []chatMessage{{Role: "user", Content: buildPrompt(query, contexts)}},
+		Stream:    g.Stream,
+		MaxTokens: g.MaxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimSuffix(g.BaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.Stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if g.APIKey != "" {
+		if g.APIKeyHeader == "" {
+			req.Header.Set("Authorization", "Bearer "+g.APIKey)
+		} else {
+			req.Header.Set(g.APIKeyHeader, g.APIKey)
+		}
+	}
+	client := g.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("generation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body chatResponse
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body) == nil && body.Error != nil {
+			return "", fmt.Errorf("server returned status: %s: %s", resp.Status, body.Error.Message)
+		}
+		return "", fmt.Errorf("server returned status: %s", resp.Status)
+	}
+
+	if !g.Stream {
+		var result chatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return "", fmt.Errorf("failed to decode response: %w", err)
+		}
+		if len(result.Choices) == 0 {
+			return "", fmt.Errorf("response has no choices")
+		}
+		return strings.TrimSpace(result.Choices[0].Message.Content), nil
+	}
+	return readStream(resp.Body, w)
+}
+
+// readStream collects the content deltas of a server-sent event stream,
+// echoing them to out, until the "[DONE]" event. A stream that ends
+// before "[DONE]" or a finish_reason was cut off and is an error, as is one
+// whose events could not be decoded and that carried no content.
+func readStream(body io.Reader, out io.Writer) (string, error) {
+	var fullResponse strings.Builder
+	var malformed error // the first event that could not be decoded
+	finished := false
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		// Events are "data: <json>" lines; comments, event names and ids are not content.
+		payload, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		payload = strings.TrimSpace(payload)
+		if payload == "[DONE]" {
+			finished = true
+			break
+		}
+		var event chatResponse
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			if malformed == nil {
+				malformed = fmt.Errorf("malformed stream event %.80q: %w", payload, err)
+			}
+			continue
+		}
+		if event.Error != nil {
+			return "", fmt.Errorf("stream error: %s", event.Error.Message)
+		}
+		if len(event.Choices) == 0 {
+			continue
+		}
+		content := event.Choices[0].Delta.Content
+		fmt.Fprint(out, content)
+		fullResponse.WriteString(content)
+		if event.Choices[0].FinishReason != "" {
+			finished = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("stream read error: %w", err)
+	}
+	if malformed != nil && fullResponse.Len() == 0 {
+		return "", malformed
+	}
+	if !finished {
+		return "", fmt.Errorf("stream ended before the answer was complete (%d bytes received)", fullResponse.Len())
+	}
+	return strings.TrimSpace(fullResponse.String()), nil
+}
+
+func (g *OpenAIGenerator) Name() string {
+	return "openai"
+}
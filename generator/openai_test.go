@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseServer answers chat completions with body, sent as a server-sent event
+// stream when the request asks for one.
+func sseServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// sse joins events into a stream body, one "data:" line each.
+func sse(events ...string) string {
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "data: %s\n\n", e)
+	}
+	return b.String()
+}
+
+func delta(content string) string {
+	return fmt.Sprintf(`{"choices":[{"delta":{"content":%q}}]}`, content)
+}
+
+func TestOpenAIGenerateStream(t *testing.T) {
+	finish := `{"choices":[{"delta":{},"finish_reason":"stop"}]}`
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr string
+	}{
+		{"done", sse(delta("Hello"), delta(" world"), "[DONE]"), "Hello world", ""},
+		{"finish reason without done", sse(delta("Hi"), finish), "Hi", ""},
+		{"comments and event names", ": ping\nevent: message\n" + sse(delta("Hi"), "[DONE]"), "Hi", ""},
+		{"stray malformed event", sse(delta("Hi"), "{oops", finish, "[DONE]"), "Hi", ""},
+		{"cut off", sse(delta("Hel")), "", "stream ended before the answer was complete"},
+		{"empty", "", "", "stream ended before the answer was complete"},
+		{"only malformed events", sse("{oops", "not json", "[DONE]"), "", "malformed stream event"},
+		{"error event", sse(delta("Hi"), `{"error":{"message":"overloaded"}}`), "", "stream error: overloaded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := sseServer(t, http.StatusOK, tt.body)
+			var out strings.Builder
+			g := NewOpenAI("m", srv.URL+"/v1", "")
+			g.Output = &out
+			got, err := g.Generate("q", []string{"ctx"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %q, %v, want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || out.String() != tt.want {
+				t.Errorf("got %q with output %q, want %q", got, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateStreamWriter(t *testing.T) {
+	srv := sseServer(t, http.StatusOK, sse(delta("Hi"), "[DONE]"))
+	var output, w strings.Builder
+	g := NewOpenAI("m", srv.URL+"/v1", "")
+	g.Output = &output
+	got, err := GenerateStream(context.Background(), g, &w, "q", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Hi" || w.String() != "Hi" || output.Len() != 0 {
+		t.Errorf("got %q, streamed %q to the writer and %q to Output, want only the writer", got, w.String(), output.String())
+	}
+}
+
+func TestOpenAIGenerateResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{"answer", http.StatusOK, `{"choices":[{"message":{"role":"assistant","content":" Hi. "}}]}`, "Hi.", ""},
+		{"no choices", http.StatusOK, `{"choices":[]}`, "", "no choices"},
+		{"status with message", http.StatusUnauthorized, `{"error":{"message":"bad key"}}`, "", "bad key"},
+		{"status", http.StatusBadGateway, `upstream down`, "", "502"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewOpenAI("m", sseServer(t, tt.status, tt.body).URL+"/v1", "")
+			g.Stream = false
+			got, err := g.Generate("q", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %q, %v, want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultClientTimeout(t *testing.T) {
+	if defaultClient.Timeout <= 0 {
+		t.Fatalf("default client has no timeout")
+	}
+	// A server that never answers must not hang a generator without a Client.
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer srv.Close()
+	defer close(hang)
+	saved := defaultClient
+	defaultClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { defaultClient = saved }()
+
+	tests := []struct {
+		name string
+		gen  Generator
+	}{
+		{"ollama", NewOllama("m", srv.URL+"/api/generate")},
+		{"openai", NewOpenAI("m", srv.URL+"/v1", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if _, err := tt.gen.Generate("q", nil); err == nil {
+				t.Error("got no error from a server that never answers")
+			}
+			if d := time.Since(start); d > 2*time.Second {
+				t.Errorf("gave up after %v", d)
+			}
+		})
+	}
+}
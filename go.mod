@@ -5,9 +5,6 @@ go 1.24.4
 require (
 	github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728
 	github.com/mattn/go-sqlite3 v1.14.28
-)
-
-require (
-	github.com/richardlehane/msoleps v1.0.3 // indirect
-	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/net v0.33.0
+	golang.org/x/text v0.21.0
 )
@@ -0,0 +1,352 @@
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// ErrArchiveLimit is returned when an archive exceeds one of the limits of
+// ArchiveReader, as zip bombs and deeply nested archives do.
+var ErrArchiveLimit = errors.New("archive limit exceeded")
+
+// ArchiveReader implements the Reader, DocumentReader, ContextReader and
+// ContextStreamReader interfaces for .zip, .tar, .tar.gz/.tgz and
+// single-file .gz archives.
+//
+// Every member whose extension has a reader in Readers becomes its own
+// document, named "!path/inside.pdf" so that it is stored as
+// "archive.zip!path/inside.pdf". Nested archives and mailboxes are expanded
+// in turn. Member names are never used as filesystem paths: members are
+// copied to temporary files, and unsafe names ("../x", "/etc/x") and links
+// are skipped.
+type ArchiveReader struct {
+	Readers      *Registry
+	MaxDepth     int   // levels of nested archives, counting the outermost
+	MaxFiles     int   // members read across all levels
+	MaxFileSize  int64 // uncompressed bytes per member
+	MaxTotalSize int64 // uncompressed bytes across all members
+}
+
+// NewArchiveReader creates an ArchiveReader that reads members with readers.
+// It allows 3 levels of nesting, 1000 members, 100 MiB per member and
+// 1 GiB in total.
+func NewArchiveReader(readers *Registry) *ArchiveReader {
+	return &ArchiveReader{
+		Readers:      readers,
+		MaxDepth:     3,
+		MaxFiles:     1000,
+		MaxFileSize:  100 << 20,
+		MaxTotalSize: 1 << 30,
+	}
+}
+
+// Extract returns the text of every supported member, separated by blank lines.
+func (r *ArchiveReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractContext is Extract, stopping between members once ctx is done.
+func (r *ArchiveReader) ExtractContext(ctx context.Context, filePath string) (string, error) {
+	docs, err := r.extractFile(filePath, 1, &archiveUsage{ctx: ctx})
+	if err != nil {
+		return "", err
+	}
+	return joinDocuments(docs), nil
+}
+
+// ExtractFrom reads an archive from ra and returns the text of every supported member.
+func (r *ArchiveReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	return r.ExtractFromContext(context.Background(), ra, size, name)
+}
+
+// ExtractFromContext is ExtractFrom, stopping between members once ctx is done.
+func (r *ArchiveReader) ExtractFromContext(ctx context.Context, ra io.ReaderAt, size int64, name string) (string, error) {
+	docs, err := r.extract(ra, size, name, 1, &archiveUsage{ctx: ctx})
+	if err != nil {
+		return "", err
+	}
+	return joinDocuments(docs), nil
+}
+
+// ExtractDocuments returns one document per supported member, in archive order.
+func (r *ArchiveReader) ExtractDocuments(filePath string) ([]types.Document, error) {
+	return r.ExtractDocumentsContext(context.Background(), filePath)
+}
+
+// ExtractDocumentsContext is ExtractDocuments, stopping between members once
+// ctx is done.
+func (r *ArchiveReader) ExtractDocumentsContext(ctx context.Context, filePath string) ([]types.Document, error) {
+	return r.extractFile(filePath, 1, &archiveUsage{ctx: ctx})
+}
+
+func joinDocuments(docs []types.Document) string {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Text != "" {
+			texts = append(texts, doc.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// archiveUsage tracks how much of the limits an archive and its nested
+// archives have used, and the context that the read is bound to.
+type archiveUsage struct {
+	ctx   context.Context
+	files int
+	bytes int64
+}
+
+func (r *ArchiveReader) extractFile(filePath string, depth int, usage *archiveUsage) ([]types.Document, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return r.extract(f, size, filePath, depth, usage)
+}
+
+func (r *ArchiveReader) extract(ra io.ReaderAt, size int64, filePath string, depth int, usage *archiveUsage) ([]types.Document, error) {
+	var docs []types.Document
+	err := r.walk(ra, size, filePath, depth, usage, func(name, memberPath string) error {
+		memberDocs, err := r.readMember(usage.ctx, memberPath)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s' in %s: %w", strings.TrimPrefix(name, "!"), filePath, err)
+		}
+		for _, doc := range memberDocs {
+			doc.Name = name + doc.Name
+			meta := map[string]string{"path": strings.TrimPrefix(doc.Name, "!")}
+			for k, v := range doc.Meta {
+				if k != "path" {
+					meta[k] = v
+				}
+			}
+			doc.Meta = meta
+			docs = append(docs, doc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// WalkMembers calls fn with the name and a temporary copy of every supported
+// member, within the limits of r, so that callers can read each member with
+// its own reader. Names start with "!" and nested archives are expanded in
+// turn, e.g. "!docs/inner.zip!guide.md". The copy keeps the member's
+// extension and is removed when fn returns.
+func (r *ArchiveReader) WalkMembers(ctx context.Context, filePath string, fn func(name, memberPath string) error) error {
+	return r.walkFile(filePath, 1, &archiveUsage{ctx: ctx}, fn)
+}
+
+func (r *ArchiveReader) walkFile(filePath string, depth int, usage *archiveUsage, fn func(name, memberPath string) error) error {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.walk(f, size, filePath, depth, usage, fn)
+}
+
+func (r *ArchiveReader) walk(ra io.ReaderAt, size int64, filePath string, depth int, usage *archiveUsage, fn func(name, memberPath string) error) error {
+	if depth > r.MaxDepth {
+		return fmt.Errorf("%w: %s is nested more than %d levels deep", ErrArchiveLimit, filePath, r.MaxDepth)
+	}
+
+	return walkArchive(ra, size, filePath, func(name string, body io.Reader) error {
+		name, ok := safeMemberName(name)
+		if !ok || !r.Readers.Supports(name) {
+			return nil
+		}
+		if err := usage.ctx.Err(); err != nil {
+			return err
+		}
+		usage.files++
+		if usage.files > r.MaxFiles {
+			return fmt.Errorf("%w: more than %d files in %s", ErrArchiveLimit, r.MaxFiles, filePath)
+		}
+
+		tmpPath, err := r.copyMember(name, body, usage)
+		if err != nil {
+			return fmt.Errorf("failed to extract '%s' from %s: %w", name, filePath, err)
+		}
+		defer os.Remove(tmpPath)
+
+		// Nested archives are expanded within the limits of the outer one.
+		if rd, err := r.Readers.Lookup(tmpPath); err == nil {
+			if _, ok := rd.(*ArchiveReader); ok {
+				err := r.walkFile(tmpPath, depth+1, usage, func(inner, memberPath string) error {
+					return fn("!"+name+inner, memberPath)
+				})
+				if err != nil {
+					return fmt.Errorf("failed to read '%s' in %s: %w", name, filePath, err)
+				}
+				return nil
+			}
+		}
+		return fn("!"+name, tmpPath)
+	})
+}
+
+// copyMember writes a member to a temporary file with the member's extension,
+// enforcing the size limits on the bytes actually read.
+func (r *ArchiveReader) copyMember(name string, body io.Reader, usage *archiveUsage) (string, error) {
+	tmp, err := os.CreateTemp("", "docai-*"+path.Ext(name))
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, io.LimitReader(body, r.MaxFileSize+1))
+	usage.bytes += n
+	switch {
+	case err != nil:
+	case n > r.MaxFileSize:
+		err = fmt.Errorf("%w: member is larger than %d bytes", ErrArchiveLimit, r.MaxFileSize)
+	case usage.bytes > r.MaxTotalSize:
+		err = fmt.Errorf("%w: members are larger than %d bytes in total", ErrArchiveLimit, r.MaxTotalSize)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// readMember extracts the documents of a member file that is not an archive.
+func (r *ArchiveReader) readMember(ctx context.Context, memberPath string) ([]types.Document, error) {
+	rd, err := r.Readers.Lookup(memberPath)
+	if err != nil {
+		return nil, err
+	}
+	if dr, ok := rd.(DocumentReader); ok {
+		return ExtractDocumentsContext(ctx, dr, memberPath)
+	}
+
+	text, err := ExtractContext(ctx, rd, memberPath)
+	if err != nil {
+		return nil, err
+	}
+	var meta map[string]string
+	if mr, ok := rd.(MetadataReader); ok {
+		if meta, err = mr.ExtractMetadata(memberPath); err != nil {
+			return nil, err
+		}
+	}
+	return []types.Document{{Text: text, Meta: meta}}, nil
+}
+
+// safeMemberName cleans a member name and rejects absolute paths, names that
+// climb out of the archive and macOS resource forks.
+func safeMemberName(name string) (string, bool) {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	switch {
+	case name == "." || name == ".." || strings.HasPrefix(name, "../"):
+		return "", false
+	case path.IsAbs(name) || filepath.VolumeName(name) != "" || (len(name) > 1 && name[1] == ':'):
+		return "", false
+	case strings.HasPrefix(name, "__MACOSX/"):
+		return "", false
+	}
+	return name, true
+}
+
+// walkArchive calls fn with the name and content of every regular file in a
+// zip, tar, gzip-compressed tar or gzip archive.
+func walkArchive(ra io.ReaderAt, size int64, filePath string, fn func(name string, body io.Reader) error) error {
+	head := readHead(ra, size, 512)
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")) || bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		zr, err := zip.NewReader(ra, size)
+		if err != nil {
+			return fmt.Errorf("failed to open %s as zip: %w", filePath, err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !f.Mode().IsRegular() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open '%s' in %s: %w", f.Name, filePath, err)
+			}
+			err = fn(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(io.NewSectionReader(ra, 0, size))
+		if err != nil {
+			return fmt.Errorf("failed to open %s as gzip: %w", filePath, err)
+		}
+		defer gz.Close()
+		br := bufio.NewReaderSize(gz, 512)
+		if inner, _ := br.Peek(262); isTarHeader(inner) {
+			return walkTar(tar.NewReader(br), filePath, fn)
+		}
+		// A single compressed file is named after the archive without ".gz".
+		name := gz.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		}
+		return fn(name, br)
+
+	case isTarHeader(head):
+		return walkTar(tar.NewReader(io.NewSectionReader(ra, 0, size)), filePath, fn)
+	}
+	return fmt.Errorf("%w: %s is not a zip, tar or gzip archive", ErrUnsupportedFormat, filePath)
+}
+
+func walkTar(tr *tar.Reader, filePath string, fn func(name string, body io.Reader) error) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive %s: %w", filePath, err)
+		}
+		// Links and devices are skipped; only regular files are read.
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// isTarHeader reports whether head starts with a POSIX or GNU tar header.
+func isTarHeader(head []byte) bool {
+	return len(head) >= 262 && bytes.Equal(head[257:262], []byte("ustar"))
+}
+
+// SniffTar matches tar archives by the "ustar" magic of their first header.
+func SniffTar(ra io.ReaderAt, size int64) bool {
+	return isTarHeader(readHead(ra, size, 512))
+}
+
+// SniffGzip matches gzip-compressed content.
+func SniffGzip(ra io.ReaderAt, size int64) bool {
+	return bytes.HasPrefix(readHead(ra, size, 2), []byte{0x1f, 0x8b})
+}
@@ -0,0 +1,130 @@
+package reader
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// tarGzBytes builds a gzip-compressed tar archive holding the given name/content pairs.
+func tarGzBytes(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for i := 0; i+1 < len(files); i += 2 {
+		hdr := &tar.Header{Name: files[i], Mode: 0o644, Size: int64(len(files[i+1])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveExtractDocuments(t *testing.T) {
+	inner := zipBytes(t, "deep.txt", "Deep text.")
+	tests := []struct {
+		name  string
+		file  string
+		data  []byte
+		names []string
+	}{
+		{
+			name:  "zip with nested archive",
+			file:  "bundle.zip",
+			data:  zipBytes(t, "a.txt", "A.", "skip.bin", "x", "sub/inner.zip", string(inner)),
+			names: []string{"!a.txt", "!sub/inner.zip!deep.txt"},
+		},
+		{
+			name:  "unsafe names skipped",
+			file:  "bundle.zip",
+			data:  zipBytes(t, "../evil.txt", "x", "/etc/x.txt", "x", "__MACOSX/a.txt", "x", "ok.txt", "ok"),
+			names: []string{"!ok.txt"},
+		},
+		{
+			name:  "tar.gz",
+			file:  "bundle.tar.gz",
+			data:  tarGzBytes(t, "docs/b.md", "# B\n\nText.\n"),
+			names: []string{"!docs/b.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := NewArchiveReader(NewDefaultRegistry()).ExtractDocuments(writeFile(t, tt.file, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, doc := range docs {
+				names = append(names, doc.Name)
+				if doc.Meta["path"] != doc.Name[1:] {
+					t.Errorf("%s: path meta = %q", doc.Name, doc.Meta["path"])
+				}
+			}
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("got %v, want %v", names, tt.names)
+			}
+		})
+	}
+}
+
+func TestArchiveWalkMembers(t *testing.T) {
+	inner := zipBytes(t, "deep.md", "# Deep\n")
+	path := writeFile(t, "bundle.zip", zipBytes(t, "a.csv", "A\n1\n", "sub/inner.zip", string(inner)))
+
+	var got []string
+	err := NewArchiveReader(NewDefaultRegistry()).WalkMembers(context.Background(), path, func(name, memberPath string) error {
+		rd, err := NewDefaultRegistry().Lookup(memberPath)
+		if err != nil {
+			return err
+		}
+		got = append(got, name+" "+FileType(memberPath, rd))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"!a.csv csv", "!sub/inner.zip!deep.md md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestArchiveLimits(t *testing.T) {
+	nested := zipBytes(t, "a.txt", "A.")
+	for i := 0; i < 3; i++ {
+		nested = zipBytes(t, "n.zip", string(nested))
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		limit func(*ArchiveReader)
+	}{
+		{"depth", nested, func(r *ArchiveReader) {}},
+		{"files", zipBytes(t, "a.txt", "A", "b.txt", "B"), func(r *ArchiveReader) { r.MaxFiles = 1 }},
+		{"file size", zipBytes(t, "a.txt", "ABCDEF"), func(r *ArchiveReader) { r.MaxFileSize = 3 }},
+		{"total size", zipBytes(t, "a.txt", "ABC", "b.txt", "DEF"), func(r *ArchiveReader) { r.MaxTotalSize = 4 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewArchiveReader(NewDefaultRegistry())
+			tt.limit(r)
+			_, err := r.ExtractDocuments(writeFile(t, "bundle.zip", tt.data))
+			if !errors.Is(err, ErrArchiveLimit) {
+				t.Errorf("got %v, want ErrArchiveLimit", err)
+			}
+		})
+	}
+}
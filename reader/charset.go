@@ -0,0 +1,136 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// DetectCharset guesses the charset of text from its byte order mark or, if
+// there is none, from its content. It returns "utf-8", "utf-16le",
+// "utf-16be", "utf-32le", "utf-32be" or, for 8-bit text that is not valid
+// UTF-8, "windows-1252", which is a superset of ISO-8859-1 for printable text.
+func DetectCharset(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE, 0, 0}):
+		return "utf-32le"
+	case bytes.HasPrefix(data, []byte{0, 0, 0xFE, 0xFF}):
+		return "utf-32be"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	}
+
+	// UTF-16 without a BOM: mostly ASCII text leaves every other byte zero.
+	sample := data[:min(len(data), 4096)]
+	if len(sample) >= 4 {
+		var even, odd int
+		for i, b := range sample {
+			if b == 0 {
+				if i%2 == 0 {
+					even++
+				} else {
+					odd++
+				}
+			}
+		}
+		half := len(sample) / 2
+		switch {
+		case odd > half*3/4 && even < half/10:
+			return "utf-16le"
+		case even > half*3/4 && odd < half/10:
+			return "utf-16be"
+		}
+	}
+
+	if utf8.Valid(data) {
+		return "utf-8"
+	}
+	return "windows-1252"
+}
+
+// DecodeText converts text read from a file to UTF-8, detecting its charset
+// with DetectCharset and dropping any byte order mark.
+func DecodeText(data []byte) string {
+	text, err := decodeCharset(DetectCharset(data), data)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), "�")
+	}
+	return text
+}
+
+// decodeCharset converts text in the named charset to UTF-8. Any charset
+// known to the WHATWG encoding standard or the IANA registry is supported,
+// e.g. UTF-16, ISO-8859-15, Windows-1252 or Shift_JIS, as well as UTF-32.
+// A leading byte order mark is dropped.
+func decodeCharset(charset string, data []byte) (string, error) {
+	enc, err := charsetEncoding(charset)
+	if err != nil {
+		return "", err
+	}
+	text, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s text: %w", charset, err)
+	}
+	return string(text), nil
+}
+
+// charsetReader adapts decodeCharset to the CharsetReader hooks of the
+// standard library's MIME and XML decoders.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := charsetEncoding(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// charsetEncoding looks up the encoding of a charset label. UTF-8 and
+// US-ASCII, and UTF-16 and UTF-32 with the byte order of the label unless a
+// byte order mark says otherwise, are handled here; plain "utf-16" and
+// "utf-32" without a byte order mark are big-endian.
+func charsetEncoding(charset string) (encoding.Encoding, error) {
+	switch normalizeCharset(charset) {
+	case "", "utf8", "usascii", "ascii":
+		return unicode.UTF8BOM, nil
+	case "utf16", "utf16be":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case "utf16le":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case "utf32", "utf32be":
+		return utf32.UTF32(utf32.BigEndian, utf32.UseBOM), nil
+	case "utf32le":
+		return utf32.UTF32(utf32.LittleEndian, utf32.UseBOM), nil
+	}
+	label := strings.TrimSpace(charset)
+	if enc, err := htmlindex.Get(label); err == nil {
+		return enc, nil
+	}
+	if enc, err := ianaindex.IANA.Encoding(label); err == nil && enc != nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+// normalizeCharset lower-cases a charset label and drops punctuation, so that
+// "ISO-8859-1", "iso_8859-1" and "ISO8859_1" compare equal.
+func normalizeCharset(charset string) string {
+	var sb strings.Builder
+	for _, c := range strings.ToLower(strings.TrimSpace(charset)) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
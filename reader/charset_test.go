@@ -0,0 +1,89 @@
+package reader
+
+import "testing"
+
+// utf16Bytes encodes ASCII text as UTF-16 without a byte order mark.
+func utf16Bytes(s string, bigEndian bool) []byte {
+	var out []byte
+	for _, c := range []byte(s) {
+		if bigEndian {
+			out = append(out, 0, c)
+		} else {
+			out = append(out, c, 0)
+		}
+	}
+	return out
+}
+
+func TestDetectCharset(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"ascii", []byte("plain text"), "utf-8"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFtext"), "utf-8"},
+		{"utf-16le bom", []byte("\xFF\xFEt\x00"), "utf-16le"},
+		{"utf-16be bom", []byte("\xFE\xFF\x00t"), "utf-16be"},
+		{"utf-32le bom", []byte("\xFF\xFE\x00\x00t\x00\x00\x00"), "utf-32le"},
+		{"utf-16le without bom", utf16Bytes("hello, world", false), "utf-16le"},
+		{"utf-16be without bom", utf16Bytes("hello, world", true), "utf-16be"},
+		{"latin-1", []byte("caf\xe9"), "windows-1252"},
+	}
+	for _, tt := range tests {
+		if got := DetectCharset(tt.data); got != tt.want {
+			t.Errorf("%s: DetectCharset() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeCharset(t *testing.T) {
+	tests := []struct {
+		charset string
+		data    []byte
+		want    string
+	}{
+		{"UTF-8", []byte("\xEF\xBB\xBFcafé"), "café"},
+		{"us-ascii", []byte("bad \xff byte"), "bad � byte"},
+		{"utf-16", []byte("\x00h\x00i"), "hi"},
+		{"utf-16", []byte("\xFF\xFEh\x00i\x00"), "hi"},
+		{"UTF-16LE", []byte("h\x00i\x00"), "hi"},
+		{"utf-32le", []byte("h\x00\x00\x00i\x00\x00\x00"), "hi"},
+		{"utf-32", []byte("\x00\x00\x00h\x00\x00\x00i"), "hi"},
+		{"ISO-8859-1", []byte("caf\xe9"), "café"},
+		{"iso_8859-15", []byte("\xa4 5"), "€ 5"},
+		{"windows-1252", []byte("\x93quoted\x94"), "“quoted”"},
+		{"Shift_JIS", []byte("\x82\xa0"), "あ"},
+		{"koi8-r", []byte("\xf0\xd2\xc9"), "При"},
+	}
+	for _, tt := range tests {
+		got, err := decodeCharset(tt.charset, tt.data)
+		if err != nil {
+			t.Errorf("decodeCharset(%q): %v", tt.charset, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("decodeCharset(%q) = %q, want %q", tt.charset, got, tt.want)
+		}
+	}
+
+	if _, err := decodeCharset("x-no-such-charset", []byte("x")); err == nil {
+		t.Error("decodeCharset accepted an unknown charset")
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte("\xEF\xBB\xBFhello"), "hello"},
+		{[]byte("\xFF\xFEh\x00i\x00"), "hi"},
+		{[]byte("na\xefve"), "naïve"},
+	}
+	for _, tt := range tests {
+		if got := DecodeText(tt.data); got != tt.want {
+			t.Errorf("DecodeText(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
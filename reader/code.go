@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// CodeReader implements the Reader and MetadataReader interfaces for source
+// code files. The text is returned unchanged apart from line endings, so that
+// chunker.CodeChunker can split it on declaration boundaries.
+type CodeReader struct{}
+
+// NewCodeReader creates a new CodeReader.
+func NewCodeReader() *CodeReader {
+	return &CodeReader{}
+}
+
+// codeLanguages maps source file extensions to language names.
+var codeLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".pyi":   "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".jsx":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".kt":    "kotlin",
+	".kts":   "kotlin",
+	".scala": "scala",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cxx":   "cpp",
+	".hpp":   "cpp",
+	".hh":    "cpp",
+	".cs":    "csharp",
+	".rs":    "rust",
+	".swift": "swift",
+	".php":   "php",
+	".rb":    "ruby",
+	".lua":   "lua",
+	".sh":    "shell",
+	".bash":  "shell",
+	".proto": "protobuf",
+	".sql":   "sql",
+}
+
+// CodeExtensions returns the file extensions handled by CodeReader.
+func CodeExtensions() []string {
+	exts := make([]string, 0, len(codeLanguages))
+	for ext := range codeLanguages {
+		exts = append(exts, ext)
+	}
+	return exts
+}
+
+// CodeLanguage returns the language of a source file from its extension,
+// e.g. "go" or "python", or "" if the extension is not a known one.
+func CodeLanguage(filePath string) string {
+	return codeLanguages[strings.ToLower(filepath.Ext(filePath))]
+}
+
+// Extract reads a source file and returns its text with LF line endings.
+func (r *CodeReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads source code from ra and returns it with LF line endings.
+func (r *CodeReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	content, err := readAllAt(ra, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read source file %s: %w", name, err)
+	}
+	return strings.ReplaceAll(DecodeText(content), "\r\n", "\n"), nil
+}
+
+// ExtractMetadata returns the language of the source file.
+func (r *CodeReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	meta := make(map[string]string)
+	if lang := CodeLanguage(filePath); lang != "" {
+		meta["language"] = lang
+	}
+	return meta, nil
+}
@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeLanguage(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"main.go", "go"},
+		{"pkg/Tool.PY", "python"},
+		{"src/app.tsx", "typescript"},
+		{"lib.hpp", "cpp"},
+		{"notes.txt", ""},
+		{"Makefile", ""},
+	}
+	for _, tt := range tests {
+		if got := CodeLanguage(tt.path); got != tt.want {
+			t.Errorf("CodeLanguage(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeReader(t *testing.T) {
+	src := "package main\r\n\r\nfunc main() {}\r\n"
+	got, err := NewCodeReader().ExtractFrom(strings.NewReader(src), int64(len(src)), "main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nfunc main() {}\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		path string
+		want map[string]string
+	}{
+		{"main.go", map[string]string{"language": "go"}},
+		{"script.rb", map[string]string{"language": "ruby"}},
+		{"README", map[string]string{}},
+	}
+	for _, tt := range tests {
+		meta, err := NewCodeReader().ExtractMetadata(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(meta) != len(tt.want) || meta["language"] != tt.want["language"] {
+			t.Errorf("ExtractMetadata(%q) = %v, want %v", tt.path, meta, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package reader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// CSVReader implements the Reader interface for delimited text files (.csv, .tsv).
+//
+// The first row is taken as the header. Every following row becomes one
+// record of "header: value" lines, and records are separated by a blank line
+// so that RowChunker can keep each row whole.
+type CSVReader struct {
+	// Comma is the field delimiter. When zero it is detected from the first
+	// line (comma, semicolon, tab or pipe), and .tsv files default to tab.
+	Comma rune
+}
+
+// NewCSVReader creates a new CSVReader that detects the delimiter.
+func NewCSVReader() *CSVReader {
+	return &CSVReader{}
+}
+
+// Extract reads a delimited file and returns one record per data row.
+func (r *CSVReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads delimited text from ra and returns one record per data
+// row. A name ending in .tsv selects tab as the default delimiter.
+func (r *CSVReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	content, err := readAllAt(ra, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read csv file %s: %w", name, err)
+	}
+	text := DecodeText(content)
+
+	comma := r.Comma
+	if comma == 0 {
+		if strings.EqualFold(filepath.Ext(name), ".tsv") {
+			comma = '\t'
+		} else {
+			comma = detectDelimiter(text[:min(len(text), 4096)])
+		}
+	}
+
+	cr := csv.NewReader(strings.NewReader(text))
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	var header []string
+	var records []string
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse csv file %s: %w", name, err)
+		}
+		if header == nil {
+			header = row
+			continue
+		}
+		if record := formatRecord(header, row); record != "" {
+			records = append(records, record)
+		}
+	}
+
+	return strings.Join(records, "\n\n"), nil
+}
+
+// detectDelimiter picks the candidate delimiter that occurs most often in the first line.
+func detectDelimiter(sample string) rune {
+	if i := strings.IndexByte(sample, '\n'); i >= 0 {
+		sample = sample[:i]
+	}
+	best, bestCount := ',', 0
+	for _, c := range []rune{',', ';', '\t', '|'} {
+		if n := strings.Count(sample, string(c)); n > bestCount {
+			best, bestCount = c, n
+		}
+	}
+	return best
+}
+
+// formatRecord renders a row as "header: value" lines, skipping empty cells.
+// Columns without a header are named "Column N".
+func formatRecord(header, row []string) string {
+	var lines []string
+	for i, value := range row {
+		value = strings.Join(strings.Fields(value), " ")
+		if value == "" {
+			continue
+		}
+		name := ""
+		if i < len(header) {
+			name = strings.Join(strings.Fields(header[i]), " ")
+		}
+		if name == "" {
+			name = fmt.Sprintf("Column %d", i+1)
+		}
+		lines = append(lines, name+": "+value)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		src  string
+		want string
+	}{
+		{
+			name: "comma",
+			file: "people.csv",
+			src:  "Name,Age\nAnn,31\nBob,\n",
+			want: "Name: Ann\nAge: 31\n\nName: Bob",
+		},
+		{
+			name: "semicolon detected",
+			file: "people.csv",
+			src:  "Name;City\nAnn;\"Paris; France\"\n",
+			want: "Name: Ann\nCity: Paris; France",
+		},
+		{
+			name: "tsv by extension",
+			file: "people.tsv",
+			src:  "Name\tNote\nAnn\ta, b\n",
+			want: "Name: Ann\nNote: a, b",
+		},
+		{
+			name: "extra columns and blank rows",
+			file: "rows.csv",
+			src:  "A\n1,2\n,\n",
+			want: "A: 1\nColumn 2: 2",
+		},
+		{
+			name: "quoted newlines collapsed",
+			file: "notes.csv",
+			src:  "Title,Body\nx,\"line one\nline two\"\n",
+			want: "Title: x\nBody: line one line two",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCSVReader().ExtractFrom(strings.NewReader(tt.src), int64(len(tt.src)), tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		sample string
+		want   rune
+	}{
+		{"a,b,c\n1;2;3;4;5", ','},
+		{"a;b;c", ';'},
+		{"a\tb", '\t'},
+		{"a|b|c", '|'},
+		{"single", ','},
+	}
+	for _, tt := range tests {
+		if got := detectDelimiter(tt.sample); got != tt.want {
+			t.Errorf("detectDelimiter(%q) = %q, want %q", tt.sample, got, tt.want)
+		}
+	}
+}
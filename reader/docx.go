@@ -5,10 +5,16 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/Ashank007/docai/types"
 )
 
-// DocxReader implements the Reader interface for .docx files.
+// DocxReader implements the Reader, SectionReader and MetadataReader
+// interfaces for .docx files. Headings are paragraphs with a Title or
+// Heading 1-9 style, or with an outline level.
 type DocxReader struct{}
 
 // NewDocxReader creates a new DocxReader.
@@ -16,64 +22,469 @@ func NewDocxReader() *DocxReader {
 	return &DocxReader{}
 }
 
+var (
+	docxHeaderPart   = regexp.MustCompile(`^word/header\d*\.xml$`)
+	docxFooterPart   = regexp.MustCompile(`^word/footer\d*\.xml$`)
+	docxHeadingStyle = regexp.MustCompile(`(?i)^heading\s*([1-9])$`)
+)
+
 // Extract reads the content of a .docx file and returns it as plain text.
+//
+// The body comes first, with tables rendered as Markdown tables. Page headers,
+// page footers, footnotes, endnotes and comments follow as labelled sections.
 func (r *DocxReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a .docx document from ra and returns it as plain text, like Extract.
+func (r *DocxReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
 	// DOCX files are essentially ZIP archives.
 	// The main text content is typically found in 'word/document.xml'.
-	zipReader, err := zip.OpenReader(filePath)
+	parts, err := zipParts(ra, size, name, "docx")
+	if err != nil {
+		return "", err
+	}
+
+	documentXML, ok := parts["word/document.xml"]
+	if !ok {
+		return "", fmt.Errorf("'word/document.xml' not found in docx file %s", name)
+	}
+	body, err := readDocxPart(documentXML)
 	if err != nil {
-		return "", fmt.Errorf("failed to open docx file %s as zip: %w", filePath, err)
+		return "", err
 	}
-	defer zipReader.Close()
 
 	var docText strings.Builder
-	foundDocumentXML := false
+	docText.WriteString(strings.TrimSpace(body))
+
+	writeSection := func(label string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		docText.WriteString("\n\n" + label + ":\n")
+		docText.WriteString(strings.Join(entries, "\n"))
+	}
+
+	headers, err := readDocxParts(parts, docxHeaderPart)
+	if err != nil {
+		return "", err
+	}
+	writeSection("Headers", headers)
+
+	footers, err := readDocxParts(parts, docxFooterPart)
+	if err != nil {
+		return "", err
+	}
+	writeSection("Footers", footers)
+
+	for _, notes := range []struct{ part, element, label string }{
+		{"word/footnotes.xml", "footnote", "Footnotes"},
+		{"word/endnotes.xml", "endnote", "Endnotes"},
+		{"word/comments.xml", "comment", "Comments"},
+	} {
+		file, ok := parts[notes.part]
+		if !ok {
+			continue
+		}
+		entries, err := readDocxNotes(file, notes.element)
+		if err != nil {
+			return "", err
+		}
+		writeSection(notes.label, entries)
+	}
+
+	// Return the extracted text, trimming leading/trailing whitespace
+	return strings.TrimSpace(docText.String()), nil
+}
+
+// ExtractSections reads the body of a .docx file and returns its text grouped
+// by heading. Page headers, footers, notes and comments are left out.
+func (r *DocxReader) ExtractSections(filePath string) ([]types.Section, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	parts, err := zipParts(f, size, filePath, "docx")
+	if err != nil {
+		return nil, err
+	}
+
+	documentXML, ok := parts["word/document.xml"]
+	if !ok {
+		return nil, fmt.Errorf("'word/document.xml' not found in docx file %s", filePath)
+	}
+	levels, err := docxHeadingLevels(parts)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := readDocxLines(documentXML, levels)
+	if err != nil {
+		return nil, err
+	}
+	return groupSections(lines), nil
+}
+
+// ExtractMetadata returns the title, author, created and other core
+// properties from docProps/core.xml.
+func (r *DocxReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	return ooxmlMetadata(filePath, "docx")
+}
+
+// readDocxPart renders the paragraphs and tables of one XML part as text.
+func readDocxPart(file *zip.File) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open '%s' in docx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	text, err := renderDocxBlocks(xml.NewDecoder(rc), "")
+	if err != nil {
+		return "", fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+	}
+	return text, nil
+}
+
+// docxHeadingLevels maps the IDs of paragraph styles that mark headings to
+// their level, from the style names and outline levels in word/styles.xml.
+func docxHeadingLevels(parts map[string]*zip.File) (map[string]int, error) {
+	levels := make(map[string]int)
+	if _, ok := parts["word/styles.xml"]; !ok {
+		return levels, nil
+	}
+	var styles struct {
+		Styles []struct {
+			Type string `xml:"type,attr"`
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+			Props docxParaProps `xml:"pPr"`
+		} `xml:"style"`
+	}
+	if err := decodeZipXML(parts, "word/styles.xml", &styles); err != nil {
+		return nil, err
+	}
+	for _, style := range styles.Styles {
+		if style.Type != "paragraph" {
+			continue
+		}
+		if m := docxHeadingStyle.FindStringSubmatch(style.Name.Val); m != nil {
+			levels[style.ID] = int(m[1][0] - '0')
+		} else if strings.EqualFold(style.Name.Val, "title") {
+			levels[style.ID] = 1
+		} else if o := style.Props.Outline; o != nil && o.Val < 9 {
+			levels[style.ID] = o.Val + 1
+		}
+	}
+	return levels, nil
+}
 
-	// Iterate through the files within the zip archive
-	for _, file := range zipReader.File {
-		if file.Name == "word/document.xml" {
-			foundDocumentXML = true
-			rc, err := file.Open()
+// readDocxLines reads the paragraphs and tables of a part as lines, marking
+// headings with their level.
+func readDocxLines(file *zip.File, levels map[string]int) ([]textLine, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s' in docx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	var lines []textLine
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch se.Name.Local {
+		case "p":
+			text, props, err := renderDocxParagraph(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				lines = append(lines, textLine{text: text, level: docxHeadingLevel(props, levels)})
+			}
+		case "tbl":
+			table, err := renderDocxTable(decoder)
 			if err != nil {
-				return "", fmt.Errorf("failed to open 'word/document.xml' in docx: %w", err)
+				return nil, fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+			}
+			lines = append(lines, textLine{text: ""}, textLine{text: table}, textLine{text: ""})
+		}
+	}
+}
+
+// docxHeadingLevel returns the heading level of a paragraph, or 0 for body
+// text. A direct outline level takes precedence over the style.
+func docxHeadingLevel(props docxParaProps, levels map[string]int) int {
+	if o := props.Outline; o != nil {
+		if o.Val < 9 {
+			return o.Val + 1
+		}
+		return 0
+	}
+	if level, ok := levels[props.Style.Val]; ok {
+		return level
+	}
+	// Documents without styles.xml still use the built-in style IDs.
+	if m := docxHeadingStyle.FindStringSubmatch(props.Style.Val); m != nil {
+		return int(m[1][0] - '0')
+	}
+	return 0
+}
+
+// readDocxParts renders every part whose name matches pattern, in name order,
+// skipping empty parts and parts identical to one already seen.
+func readDocxParts(parts map[string]*zip.File, pattern *regexp.Regexp) ([]string, error) {
+	var matched []*zip.File
+	for _, f := range parts {
+		if pattern.MatchString(f.Name) {
+			matched = append(matched, f)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+
+	seen := make(map[string]bool)
+	var texts []string
+	for _, f := range matched {
+		text, err := readDocxPart(f)
+		if err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+// readDocxNotes renders each <w:footnote>, <w:endnote> or <w:comment> of a part
+// as one "[label id] text" entry. Separator notes used for layout are skipped.
+func readDocxNotes(file *zip.File, element string) ([]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s' in docx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	var entries []string
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok || se.Name.Local != element {
+			continue
+		}
+
+		id, author, noteType := xmlAttr(se, "id"), xmlAttr(se, "author"), xmlAttr(se, "type")
+		text, err := renderDocxBlocks(decoder, element)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing '%s' in docx: %w", file.Name, err)
+		}
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" || noteType == "separator" || noteType == "continuationSeparator" || noteType == "continuationNotice" {
+			continue
+		}
+
+		entry := fmt.Sprintf("[%s %s] ", element, id)
+		if author != "" {
+			entry += author + ": "
+		}
+		entries = append(entries, entry+text)
+	}
+	return entries, nil
+}
+
+// renderDocxBlocks reads paragraphs and tables until the end of the element
+// named end (or the end of input when end is empty) and returns their text.
+func renderDocxBlocks(decoder *xml.Decoder, end string) (string, error) {
+	var sb strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "p":
+				text, _, err := renderDocxParagraph(decoder)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(text)
+				sb.WriteString("\n")
+			case "tbl":
+				table, err := renderDocxTable(decoder)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString("\n")
+				sb.WriteString(table)
+				sb.WriteString("\n\n")
 			}
-			defer rc.Close()
-
-			// XML parser to extract text
-			decoder := xml.NewDecoder(rc)
-			for {
-				token, err := decoder.Token()
-				if err == io.EOF {
-					break // End of XML file
+		case xml.EndElement:
+			if se.Name.Local == end {
+				return sb.String(), nil
+			}
+		}
+	}
+}
+
+// docxParaProps holds the paragraph properties that mark a heading.
+type docxParaProps struct {
+	Style struct {
+		Val string `xml:"val,attr"`
+	} `xml:"pStyle"`
+	Outline *struct {
+		Val int `xml:"val,attr"`
+	} `xml:"outlineLvl"`
+}
+
+// renderDocxParagraph reads the runs of a <w:p> and returns its text and
+// properties. Tabs and breaks become whitespace; note and comment references
+// become markers.
+func renderDocxParagraph(decoder *xml.Decoder) (string, docxParaProps, error) {
+	var sb strings.Builder
+	var props docxParaProps
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", props, err
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "pPr":
+				// Paragraph properties carry the style; their tab stop definitions (<w:tabs><w:tab/>) are not content.
+				if err := decoder.DecodeElement(&props, &se); err != nil {
+					return "", props, err
+				}
+			case "rPr":
+				if err := decoder.Skip(); err != nil {
+					return "", props, err
 				}
+			case "t":
+				// Look for <w:t> (text run) tags for actual text
+				var textContent string
+				if err := decoder.DecodeElement(&textContent, &se); err != nil {
+					return "", props, fmt.Errorf("error decoding text element: %w", err)
+				}
+				sb.WriteString(textContent)
+			case "tab":
+				sb.WriteString("\t")
+			case "br", "cr":
+				sb.WriteString("\n")
+			case "footnoteReference":
+				sb.WriteString(fmt.Sprintf("[footnote %s]", xmlAttr(se, "id")))
+			case "endnoteReference":
+				sb.WriteString(fmt.Sprintf("[endnote %s]", xmlAttr(se, "id")))
+			case "commentReference":
+				sb.WriteString(fmt.Sprintf("[comment %s]", xmlAttr(se, "id")))
+			case "p":
+				// Paragraphs nested in text boxes are inlined into the outer paragraph.
+				inner, _, err := renderDocxParagraph(decoder)
 				if err != nil {
-					return "", fmt.Errorf("error parsing 'word/document.xml' in docx: %w", err)
+					return "", props, err
 				}
+				sb.WriteString(" " + inner + " ")
+			}
+		case xml.EndElement:
+			if se.Name.Local == "p" {
+				return sb.String(), props, nil
+			}
+		}
+	}
+}
 
-				switch se := token.(type) {
-				case xml.StartElement:
-					// Look for <w:t> (text run) tags for actual text
-					if se.Name.Local == "t" {
-						var textContent string
-						if err := decoder.DecodeElement(&textContent, &se); err != nil {
-							return "", fmt.Errorf("error decoding text element: %w", err)
-						}
-						docText.WriteString(textContent)
-					} else if se.Name.Local == "p" {
-						// Add a newline for paragraph breaks to maintain readability
-						docText.WriteString("\n")
-					}
+// renderDocxTable reads a <w:tbl> and returns it as a Markdown table.
+func renderDocxTable(decoder *xml.Decoder) (string, error) {
+	var rows [][]string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "tr":
+				rows = append(rows, nil)
+			case "tc":
+				cell, err := renderDocxBlocks(decoder, "tc")
+				if err != nil {
+					return "", err
 				}
+				cell = strings.Join(strings.Fields(cell), " ")
+				cell = strings.ReplaceAll(cell, "|", `\|`)
+				if len(rows) == 0 {
+					rows = append(rows, nil)
+				}
+				rows[len(rows)-1] = append(rows[len(rows)-1], cell)
+			}
+		case xml.EndElement:
+			if se.Name.Local == "tbl" {
+				return markdownTable(rows), nil
 			}
-			break // We found and processed document.xml, no need to check other files
 		}
 	}
+}
 
-	if !foundDocumentXML {
-		return "", fmt.Errorf("'word/document.xml' not found in docx file %s", filePath)
+// markdownTable renders rows as a Markdown table, treating the first row as the header.
+func markdownTable(rows [][]string) string {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if width == 0 {
+		return ""
 	}
 
-	// Return the extracted text, trimming leading/trailing whitespace
-	return strings.TrimSpace(docText.String()), nil
+	var sb strings.Builder
+	for i, row := range rows {
+		cells := make([]string, width)
+		copy(cells, row)
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
 }
 
+// xmlAttr returns the value of the attribute with the given local name.
+func xmlAttr(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
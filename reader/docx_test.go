@@ -0,0 +1,122 @@
+package reader
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+const docxNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// docxPara returns a <w:p> with the given style (may be empty) and text.
+func docxPara(style, text string) string {
+	props := ""
+	if style != "" {
+		props = `<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`
+	}
+	return `<w:p>` + props + `<w:r><w:t xml:space="preserve">` + text + `</w:t></w:r></w:p>`
+}
+
+func docxBody(blocks ...string) string {
+	return `<?xml version="1.0"?><w:document ` + docxNS + `><w:body>` + strings.Join(blocks, "") + `</w:body></w:document>`
+}
+
+func TestDocxExtract(t *testing.T) {
+	table := `<w:tbl><w:tr><w:tc>` + docxPara("", "Name") + `</w:tc><w:tc>` + docxPara("", "Qty") + `</w:tc></w:tr>` +
+		`<w:tr><w:tc>` + docxPara("", "Tea|Green") + `</w:tc><w:tc>` + docxPara("", "2") + `</w:tc></w:tr></w:tbl>`
+	noteRef := `<w:p><w:r><w:t>See note</w:t></w:r><w:r><w:footnoteReference w:id="1"/></w:r><w:r><w:tab/><w:t>end</w:t></w:r></w:p>`
+	file := zipBytes(t,
+		"word/document.xml", docxBody(docxPara("", "Intro paragraph."), table, noteRef),
+		"word/header1.xml", `<w:hdr `+docxNS+`>`+docxPara("", "Company header")+`</w:hdr>`,
+		"word/header2.xml", `<w:hdr `+docxNS+`>`+docxPara("", "Company header")+`</w:hdr>`,
+		"word/footer1.xml", `<w:ftr `+docxNS+`>`+docxPara("", "Page footer")+`</w:ftr>`,
+		"word/footnotes.xml", `<w:footnotes `+docxNS+`><w:footnote w:type="separator" w:id="0">`+docxPara("", "---")+`</w:footnote>`+
+			`<w:footnote w:id="1">`+docxPara("", "The note text.")+`</w:footnote></w:footnotes>`,
+		"word/comments.xml", `<w:comments `+docxNS+`><w:comment w:id="3" w:author="Ann">`+docxPara("", "Check this.")+`</w:comment></w:comments>`,
+	)
+
+	got, err := NewDocxReader().ExtractFrom(bytes.NewReader(file), int64(len(file)), "doc.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Intro paragraph.
+
+| Name | Qty |
+| --- | --- |
+| Tea\|Green | 2 |
+
+See note[footnote 1]	end
+
+Headers:
+Company header
+
+Footers:
+Page footer
+
+Footnotes:
+[footnote 1] The note text.
+
+Comments:
+[comment 3] Ann: Check this.`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocxExtractMissingDocument(t *testing.T) {
+	file := zipBytes(t, "word/styles.xml", "<w:styles/>")
+	if _, err := NewDocxReader().ExtractFrom(bytes.NewReader(file), int64(len(file)), "doc.docx"); err == nil {
+		t.Error("expected an error for a docx without word/document.xml")
+	}
+}
+
+func TestDocxExtractSections(t *testing.T) {
+	styles := `<w:styles ` + docxNS + `>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>
+<w:style w:type="paragraph" w:styleId="Custom"><w:name w:val="Chapter"/><w:pPr><w:outlineLvl w:val="1"/></w:pPr></w:style>
+</w:styles>`
+	outline := `<w:p><w:pPr><w:outlineLvl w:val="2"/></w:pPr><w:r><w:t>Deep</w:t></w:r></w:p>`
+	file := zipBytes(t,
+		"word/document.xml", docxBody(
+			docxPara("Title", "Manual"),
+			docxPara("", "Preface."),
+			docxPara("Heading1", "Setup"),
+			docxPara("Custom", "Linux"),
+			docxPara("", "Install it."),
+			outline,
+			docxPara("", "Details."),
+		),
+		"word/styles.xml", styles,
+	)
+	sections, err := NewDocxReader().ExtractSections(writeFile(t, "doc.docx", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Section{
+		{Headings: []string{"Manual"}, Text: "Preface."},
+		{Headings: []string{"Setup", "Linux"}, Text: "Install it."},
+		{Headings: []string{"Setup", "Linux", "Deep"}, Text: "Details."},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("got %+v, want %+v", sections, want)
+	}
+}
+
+func TestDocxExtractMetadata(t *testing.T) {
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">
+<dc:title>Plan</dc:title><dc:creator>Bob</dc:creator><dcterms:created>2024-05-01T10:00:00Z</dcterms:created></cp:coreProperties>`
+	file := zipBytes(t, "word/document.xml", docxBody(), "docProps/core.xml", core)
+	meta, err := NewDocxReader().ExtractMetadata(writeFile(t, "doc.docx", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"title": "Plan", "author": "Bob", "created": "2024-05-01T10:00:00Z"}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("got %v, want %v", meta, want)
+	}
+}
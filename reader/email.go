@@ -0,0 +1,294 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// EmailReader implements the Reader, DocumentReader and MetadataReader
+// interfaces for single RFC 5322 messages (.eml).
+//
+// MIME bodies are decoded (quoted-printable, base64 and the charset of each
+// part), text/plain is preferred over text/html, and attachments are skipped.
+// From, To, Cc, Date and Subject head the text and are kept as metadata.
+type EmailReader struct{}
+
+// NewEmailReader creates a new EmailReader.
+func NewEmailReader() *EmailReader {
+	return &EmailReader{}
+}
+
+// Extract returns the message headers followed by its body text.
+func (r *EmailReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a message from ra and returns its headers followed by its body text.
+func (r *EmailReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	raw, err := readAllAt(ra, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read email file %s: %w", name, err)
+	}
+	doc, err := parseEmail(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email file %s: %w", name, err)
+	}
+	return doc.Text, nil
+}
+
+// ExtractDocuments returns the message as a single unnamed document.
+func (r *EmailReader) ExtractDocuments(filePath string) ([]types.Document, error) {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email file %s: %w", filePath, err)
+	}
+	doc, err := parseEmail(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email file %s: %w", filePath, err)
+	}
+	return []types.Document{doc}, nil
+}
+
+// ExtractMetadata returns the from, to, cc, date, subject and message-id of the message.
+func (r *EmailReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	docs, err := r.ExtractDocuments(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return docs[0].Meta, nil
+}
+
+// MboxReader implements the Reader and DocumentReader interfaces for mbox
+// mailboxes. Every message is decoded as by EmailReader and becomes its own
+// document, named "#N" after its one-based position in the mailbox.
+type MboxReader struct{}
+
+// NewMboxReader creates a new MboxReader.
+func NewMboxReader() *MboxReader {
+	return &MboxReader{}
+}
+
+// Extract returns every message in the mailbox, separated by blank lines.
+func (r *MboxReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a mailbox from ra and returns every message, separated by blank lines.
+func (r *MboxReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	docs, err := mboxDocuments(io.NewSectionReader(ra, 0, size), name)
+	if err != nil {
+		return "", err
+	}
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.Text)
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// ExtractDocuments returns one document per message, in mailbox order.
+func (r *MboxReader) ExtractDocuments(filePath string) ([]types.Document, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mbox file %s: %w", filePath, err)
+	}
+	defer file.Close()
+	return mboxDocuments(file, filePath)
+}
+
+func mboxDocuments(r io.Reader, name string) ([]types.Document, error) {
+	var docs []types.Document
+	err := readMbox(r, func(raw []byte) error {
+		doc, err := parseEmail(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse message %d in %s: %w", len(docs)+1, name, err)
+		}
+		doc.Name = "#" + strconv.Itoa(len(docs)+1)
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+var mboxFromLine = regexp.MustCompile(`^From \S+ +(Mon|Tue|Wed|Thu|Fri|Sat|Sun) `)
+
+// SniffMbox matches content whose first line is an mbox "From " separator.
+func SniffMbox(ra io.ReaderAt, size int64) bool {
+	return mboxFromLine.Match(readHead(ra, size, 256))
+}
+
+// readMbox splits a mailbox on its "From " separator lines and calls fn with
+// each raw message, undoing the ">From " quoting of body lines. As in mboxo
+// and mboxrd, a separator starts the file or follows a blank line, so an
+// unquoted "From " line inside a body does not split the message.
+func readMbox(r io.Reader, fn func(raw []byte) error) error {
+	br := bufio.NewReader(r)
+	var msg bytes.Buffer
+	started := false
+	afterBlank := true // at the start of the file or after a blank line
+	emit := func() error {
+		if !started || len(bytes.TrimSpace(msg.Bytes())) == 0 {
+			return nil
+		}
+		return fn(bytes.Clone(msg.Bytes()))
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			separator := afterBlank && bytes.HasPrefix(line, []byte("From "))
+			afterBlank = len(bytes.TrimRight(line, "\r\n")) == 0
+			switch {
+			case separator:
+				if err := emit(); err != nil {
+					return err
+				}
+				msg.Reset()
+				started = true
+			case started:
+				if quoted := bytes.TrimLeft(line, ">"); len(quoted) < len(line) && bytes.HasPrefix(quoted, []byte("From ")) {
+					line = line[1:]
+				}
+				msg.Write(line)
+			}
+		}
+		if err == io.EOF {
+			return emit()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read mbox: %w", err)
+		}
+	}
+}
+
+// emailHeaders lists the headers kept as metadata, in the order they head the text.
+var emailHeaders = []string{"From", "To", "Cc", "Date", "Subject"}
+
+// parseEmail decodes one raw message into a document whose text is the
+// message's main headers followed by its body.
+func parseEmail(raw []byte) (types.Document, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return types.Document{}, err
+	}
+
+	words := &mime.WordDecoder{CharsetReader: charsetReader}
+	meta := make(map[string]string)
+	var sb strings.Builder
+	for _, name := range emailHeaders {
+		value := msg.Header.Get(name)
+		if decoded, err := words.DecodeHeader(value); err == nil {
+			value = decoded
+		}
+		value = strings.Join(strings.Fields(value), " ")
+		if name == "Date" {
+			if t, err := mail.ParseDate(value); err == nil {
+				value = t.Format(time.RFC3339)
+			}
+		}
+		if value == "" {
+			continue
+		}
+		meta[strings.ToLower(name)] = value
+		sb.WriteString(name + ": " + value + "\n")
+	}
+	if id := strings.Trim(msg.Header.Get("Message-Id"), "<> \t"); id != "" {
+		meta["message-id"] = id
+	}
+
+	plain, html, err := emailBody(msg.Header, msg.Body)
+	if err != nil {
+		return types.Document{}, err
+	}
+	body := strings.TrimSpace(plain)
+	if body == "" && html != "" {
+		body = renderLines(htmlContentLines(parseHTML(html)))
+	}
+	if body != "" {
+		sb.WriteString("\n" + body)
+	}
+
+	return types.Document{Text: strings.TrimSpace(sb.String()), Meta: meta}, nil
+}
+
+// mimeHeader is satisfied by both mail.Header and textproto.MIMEHeader.
+type mimeHeader interface {
+	Get(key string) string
+}
+
+// emailBody walks a MIME entity and returns the first text/plain and the
+// first text/html body outside attachments, decoded to UTF-8.
+func emailBody(header mimeHeader, body io.Reader) (plain, html string, err error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+	if disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition")); disposition == "attachment" {
+		return "", "", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return plain, html, nil
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read MIME part: %w", err)
+			}
+			p, h, err := emailBody(part.Header, part)
+			if err != nil {
+				return "", "", err
+			}
+			if plain == "" {
+				plain = p
+			}
+			if html == "" {
+				html = h
+			}
+		}
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s body: %w", mediaType, err)
+	}
+	text, err := decodeCharset(params["charset"], data)
+	if err != nil {
+		text = DecodeText(data)
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	if mediaType == "text/html" {
+		return "", text, nil
+	}
+	return text, "", nil
+}
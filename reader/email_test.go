@@ -0,0 +1,163 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEmailExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "plain",
+			src:  "From: Ann <ann@example.com>\r\nTo: bob@example.com\r\nSubject: Hello\r\n\r\nHi Bob,\r\nsee you.\r\n",
+			want: "From: Ann <ann@example.com>\nTo: bob@example.com\nSubject: Hello\n\nHi Bob,\nsee you.",
+		},
+		{
+			name: "encoded subject and quoted-printable body",
+			src: "Subject: =?UTF-8?Q?Caf=C3=A9?=\n" +
+				"Content-Type: text/plain; charset=utf-8\n" +
+				"Content-Transfer-Encoding: quoted-printable\n\n" +
+				"Un caf=C3=A9 =\nsvp\n",
+			want: "Subject: Café\n\nUn café svp",
+		},
+		{
+			name: "date normalized",
+			src:  "Date: Mon, 2 Jan 2006 15:04:05 +0000\n\nbody\n",
+			want: "Date: 2006-01-02T15:04:05Z\n\nbody",
+		},
+		{
+			name: "plain preferred over html, attachment skipped",
+			src: "Subject: Mixed\n" +
+				"Content-Type: multipart/mixed; boundary=outer\n\n" +
+				"--outer\n" +
+				"Content-Type: multipart/alternative; boundary=inner\n\n" +
+				"--inner\n" +
+				"Content-Type: text/html\n\n" +
+				"<p>html body</p>\n" +
+				"--inner\n" +
+				"Content-Type: text/plain\n" +
+				"Content-Transfer-Encoding: base64\n\n" +
+				"cGxhaW4gYm9keQ==\n" +
+				"--inner--\n" +
+				"--outer\n" +
+				"Content-Type: text/plain\n" +
+				"Content-Disposition: attachment; filename=a.txt\n\n" +
+				"attached\n" +
+				"--outer--\n",
+			want: "Subject: Mixed\n\nplain body",
+		},
+		{
+			name: "html fallback",
+			src: "Subject: Only HTML\n" +
+				"Content-Type: text/html; charset=iso-8859-1\n\n" +
+				"<html><body><h1>Title</h1><p>Gr\xfc\xdfe</p></body></html>\n",
+			want: "Subject: Only HTML\n\nTitle\nGrüße",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmailReader().ExtractFrom(strings.NewReader(tt.src), int64(len(tt.src)), "msg.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailExtractMetadata(t *testing.T) {
+	src := "From: ann@example.com\nCc: carl@example.com\nSubject: Notes\nMessage-ID: <42@example.com>\n\nbody\n"
+	got, err := NewEmailReader().ExtractMetadata(writeFile(t, "msg.eml", []byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"from":       "ann@example.com",
+		"cc":         "carl@example.com",
+		"subject":    "Notes",
+		"message-id": "42@example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMboxExtractDocuments(t *testing.T) {
+	type doc struct{ name, text string }
+	tests := []struct {
+		name string
+		src  string
+		docs []doc
+	}{
+		{
+			name: "quoted From line",
+			src: "From ann@example.com Mon Jan  2 15:04:05 2006\n" +
+				"Subject: One\n\n" +
+				"first\n>From the start\n\n" +
+				"From bob@example.com Tue Jan  3 15:04:05 2006\n" +
+				"Subject: Two\n\n" +
+				"second\n",
+			docs: []doc{{"#1", "Subject: One\n\nfirst\nFrom the start"}, {"#2", "Subject: Two\n\nsecond"}},
+		},
+		{
+			name: "unquoted From line in a body",
+			src: "From ann@example.com Mon Jan  2 15:04:05 2006\n" +
+				"Subject: One\n\n" +
+				"We agreed.\nFrom here on, we meet weekly.\n" +
+				"From the notes: nothing else.\n\n" +
+				"From bob@example.com Tue Jan  3 15:04:05 2006\n" +
+				"Subject: Two\n\n" +
+				"second\n",
+			docs: []doc{
+				{"#1", "Subject: One\n\nWe agreed.\nFrom here on, we meet weekly.\nFrom the notes: nothing else."},
+				{"#2", "Subject: Two\n\nsecond"},
+			},
+		},
+		{
+			name: "CRLF blank line before separator",
+			src: "From ann@example.com Mon Jan  2 15:04:05 2006\r\n" +
+				"Subject: One\r\n\r\nfirst\r\n\r\n" +
+				"From bob@example.com Tue Jan  3 15:04:05 2006\r\n" +
+				"Subject: Two\r\n\r\nsecond\r\n",
+			docs: []doc{{"#1", "Subject: One\n\nfirst"}, {"#2", "Subject: Two\n\nsecond"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := NewMboxReader().ExtractDocuments(writeFile(t, "mail.mbox", []byte(tt.src)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []doc
+			for _, d := range docs {
+				got = append(got, doc{d.Name, d.Text})
+			}
+			if !reflect.DeepEqual(got, tt.docs) {
+				t.Errorf("got %q, want %q", got, tt.docs)
+			}
+		})
+	}
+}
+
+func TestSniffMbox(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"From ann@example.com Mon Jan  2 15:04:05 2006\nSubject: x\n", true},
+		{"From: ann@example.com\nSubject: x\n", false},
+		{"From here on, plain text.\n", false},
+	}
+	for _, tt := range tests {
+		if got := SniffMbox(strings.NewReader(tt.src), int64(len(tt.src))); got != tt.want {
+			t.Errorf("SniffMbox(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
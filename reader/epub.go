@@ -0,0 +1,299 @@
+package reader
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+	"golang.org/x/net/html"
+)
+
+// EPUBReader implements the Reader, PageReader, SectionReader and
+// MetadataReader interfaces for EPUB books (.epub).
+//
+// Content documents are read in OPF spine order. Each spine document is one
+// page, so Chunk.Page holds the chapter index, and the chapter title from the
+// table of contents heads the section path of its text.
+type EPUBReader struct{}
+
+// NewEPUBReader creates a new EPUBReader.
+func NewEPUBReader() *EPUBReader {
+	return &EPUBReader{}
+}
+
+// Extract returns the text of every chapter in reading order.
+func (r *EPUBReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a book from ra and returns the text of every chapter in reading order.
+func (r *EPUBReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	book, err := loadEPUB(ra, size, name)
+	if err != nil {
+		return "", err
+	}
+	return joinPages(book.pages()), nil
+}
+
+// ExtractPages returns one page per spine document, in reading order.
+func (r *EPUBReader) ExtractPages(filePath string) ([]string, error) {
+	book, err := loadEPUBFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return book.pages(), nil
+}
+
+// ExtractSections returns the book's text grouped by heading, with the
+// chapter title as the first element of every heading path. Page holds the
+// chapter's position in the spine, as in ExtractPages.
+func (r *EPUBReader) ExtractSections(filePath string) ([]types.Section, error) {
+	book, err := loadEPUBFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var sections []types.Section
+	for i, ch := range book.chapters {
+		for _, s := range groupSections(ch.lines) {
+			if ch.title != "" && (len(s.Headings) == 0 || s.Headings[0] != ch.title) {
+				s.Headings = append([]string{ch.title}, s.Headings...)
+			}
+			s.Page = i + 1
+			sections = append(sections, s)
+		}
+	}
+	return sections, nil
+}
+
+// ExtractMetadata returns the Dublin Core title, author, language, publisher and date of the book.
+func (r *EPUBReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	book, err := loadEPUBFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return book.meta, nil
+}
+
+type epubChapter struct {
+	title string
+	lines []textLine
+}
+
+type epubBook struct {
+	meta     map[string]string
+	chapters []epubChapter
+}
+
+func (b *epubBook) pages() []string {
+	pages := make([]string, len(b.chapters))
+	for i, ch := range b.chapters {
+		pages[i] = renderLines(ch.lines)
+	}
+	return pages
+}
+
+// epubPackage is the subset of the OPF package document that the reader needs.
+type epubPackage struct {
+	Metadata struct {
+		Title     []string `xml:"title"`
+		Creator   []string `xml:"creator"`
+		Language  []string `xml:"language"`
+		Publisher []string `xml:"publisher"`
+		Date      []string `xml:"date"`
+	} `xml:"metadata"`
+	Manifest []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Spine struct {
+		Toc      string `xml:"toc,attr"`
+		ItemRefs []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+func loadEPUBFile(filePath string) (*epubBook, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return loadEPUB(f, size, filePath)
+}
+
+// loadEPUB reads the container, package document, table of contents and
+// spine documents of an EPUB file.
+func loadEPUB(ra io.ReaderAt, size int64, name string) (*epubBook, error) {
+	parts, err := zipParts(ra, size, name, "epub")
+	if err != nil {
+		return nil, err
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeZipXML(parts, "META-INF/container.xml", &container); err != nil {
+		return nil, err
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, fmt.Errorf("no package document listed in epub file %s", name)
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg epubPackage
+	if err := decodeZipXML(parts, opfPath, &pkg); err != nil {
+		return nil, err
+	}
+	opfDir := path.Dir(opfPath)
+
+	book := &epubBook{meta: make(map[string]string)}
+	for key, values := range map[string][]string{
+		"title":     pkg.Metadata.Title,
+		"author":    pkg.Metadata.Creator,
+		"language":  pkg.Metadata.Language,
+		"publisher": pkg.Metadata.Publisher,
+		"date":      pkg.Metadata.Date,
+	} {
+		if len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+			book.meta[key] = strings.TrimSpace(strings.Join(values, ", "))
+		}
+	}
+
+	hrefs := make(map[string]string)
+	var navPath, ncxPath string
+	for _, item := range pkg.Manifest {
+		full := resolveEPUBHref(opfDir, item.Href)
+		hrefs[item.ID] = full
+		if strings.Contains(" "+item.Properties+" ", " nav ") {
+			navPath = full
+		}
+		if item.ID == pkg.Spine.Toc || item.MediaType == "application/x-dtbncx+xml" {
+			ncxPath = full
+		}
+	}
+
+	titles, err := epubTOC(parts, navPath, ncxPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range pkg.Spine.ItemRefs {
+		docPath, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		file, ok := parts[docPath]
+		if !ok {
+			return nil, fmt.Errorf("spine document '%s' not found in epub file %s", docPath, name)
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		doc := parseHTML(string(content))
+		lines := htmlContentLines(doc)
+
+		title := titles[docPath]
+		if title == "" {
+			for _, l := range lines {
+				if l.level > 0 {
+					title = l.text
+					break
+				}
+			}
+		}
+		book.chapters = append(book.chapters, epubChapter{title: title, lines: lines})
+	}
+	return book, nil
+}
+
+// epubTOC maps content document paths to their chapter titles, preferring the
+// EPUB 3 navigation document and falling back to the EPUB 2 NCX.
+func epubTOC(parts map[string]*zip.File, navPath, ncxPath string) (map[string]string, error) {
+	titles := make(map[string]string)
+	add := func(baseDir, href, title string) {
+		target := resolveEPUBHref(baseDir, href)
+		title = strings.Join(strings.Fields(title), " ")
+		if _, seen := titles[target]; !seen && title != "" {
+			titles[target] = title
+		}
+	}
+
+	if file, ok := parts[navPath]; ok && navPath != "" {
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		nav := parseHTML(string(content))
+		toc := findHTML(nav, func(n *html.Node) bool {
+			return isElement(n, "nav") && strings.Contains(htmlAttr(n, "epub:type"), "toc")
+		})
+		if toc == nil {
+			toc = findHTML(nav, func(n *html.Node) bool { return isElement(n, "nav") })
+		}
+		if toc != nil {
+			walkHTML(toc, func(n *html.Node) {
+				if isElement(n, "a") && htmlAttr(n, "href") != "" {
+					// inlineText drops text under <nav>, so collect the label directly.
+					var label strings.Builder
+					walkHTML(n, func(c *html.Node) {
+						if c.Type == html.TextNode {
+							label.WriteString(c.Data + " ")
+						}
+					})
+					add(path.Dir(navPath), htmlAttr(n, "href"), label.String())
+				}
+			})
+		}
+		if len(titles) > 0 {
+			return titles, nil
+		}
+	}
+
+	if _, ok := parts[ncxPath]; ok && ncxPath != "" {
+		var ncx struct {
+			Points []epubNavPoint `xml:"navMap>navPoint"`
+		}
+		if err := decodeZipXML(parts, ncxPath, &ncx); err != nil {
+			return nil, err
+		}
+		var visit func(points []epubNavPoint)
+		visit = func(points []epubNavPoint) {
+			for _, p := range points {
+				add(path.Dir(ncxPath), p.Content.Src, p.Label)
+				visit(p.Children)
+			}
+		}
+		visit(ncx.Points)
+	}
+	return titles, nil
+}
+
+type epubNavPoint struct {
+	Label   string `xml:"navLabel>text"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []epubNavPoint `xml:"navPoint"`
+}
+
+// resolveEPUBHref resolves a manifest or TOC href against baseDir, dropping
+// any fragment and percent-encoding.
+func resolveEPUBHref(baseDir, href string) string {
+	if i := strings.IndexByte(href, '#'); i >= 0 {
+		href = href[:i]
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Join(baseDir, href)
+}
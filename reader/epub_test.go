@@ -0,0 +1,86 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// epubFixture returns an EPUB 3 book with a navigation document and two
+// chapters, the second of which has no title in the table of contents.
+func epubFixture(t *testing.T) []byte {
+	t.Helper()
+	container := `<container xmlns="urn:oasis:names:tc:opendocument:xmlns:container"><rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`
+	opf := `<package xmlns="http://www.idpf.org/2007/opf" xmlns:dc="http://purl.org/dc/elements/1.1/" version="3.0">
+<metadata><dc:title>The Book</dc:title><dc:creator>Ann</dc:creator><dc:creator>Bob</dc:creator><dc:language>en</dc:language></metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="c1" href="text/ch%201.xhtml" media-type="application/xhtml+xml"/>
+<item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/>
+</manifest>
+<spine><itemref idref="c1"/><itemref idref="c2"/></spine></package>`
+	nav := `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+<nav epub:type="toc"><ol><li><a href="text/ch%201.xhtml#start">Chapter  One</a></li></ol></nav></body></html>`
+	ch1 := `<html xmlns="http://www.w3.org/1999/xhtml"><body><p>It begins.</p><h2>A Scene</h2><p>Rain falls.</p></body></html>`
+	ch2 := `<html xmlns="http://www.w3.org/1999/xhtml"><body><h1>Two</h1><p>It ends.</p></body></html>`
+	return zipBytes(t,
+		"mimetype", "application/epub+zip",
+		"META-INF/container.xml", container,
+		"OEBPS/content.opf", opf,
+		"OEBPS/nav.xhtml", nav,
+		"OEBPS/text/ch 1.xhtml", ch1,
+		"OEBPS/text/ch2.xhtml", ch2,
+	)
+}
+
+func TestEPUBExtractPages(t *testing.T) {
+	pages, err := NewEPUBReader().ExtractPages(writeFile(t, "book.epub", epubFixture(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"It begins.\n\nA Scene\nRain falls.", "Two\nIt ends."}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("got %q, want %q", pages, want)
+	}
+}
+
+func TestEPUBExtractSections(t *testing.T) {
+	sections, err := NewEPUBReader().ExtractSections(writeFile(t, "book.epub", epubFixture(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Section{
+		{Headings: []string{"Chapter One"}, Text: "It begins.", Page: 1},
+		{Headings: []string{"Chapter One", "A Scene"}, Text: "Rain falls.", Page: 1},
+		{Headings: []string{"Two"}, Text: "It ends.", Page: 2},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("got %+v, want %+v", sections, want)
+	}
+}
+
+func TestEPUBExtractMetadata(t *testing.T) {
+	meta, err := NewEPUBReader().ExtractMetadata(writeFile(t, "book.epub", epubFixture(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"title": "The Book", "author": "Ann, Bob", "language": "en"}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("got %v, want %v", meta, want)
+	}
+}
+
+func TestResolveEPUBHref(t *testing.T) {
+	tests := []struct{ base, href, want string }{
+		{"OEBPS", "text/ch1.xhtml#p3", "OEBPS/text/ch1.xhtml"},
+		{"OEBPS/text", "../images/a%20b.png", "OEBPS/images/a b.png"},
+		{".", "ch.xhtml", "ch.xhtml"},
+	}
+	for _, tt := range tests {
+		if got := resolveEPUBHref(tt.base, tt.href); got != tt.want {
+			t.Errorf("resolveEPUBHref(%q, %q) = %q, want %q", tt.base, tt.href, got, tt.want)
+		}
+	}
+}
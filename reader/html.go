@@ -0,0 +1,381 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+	"golang.org/x/net/html"
+)
+
+// HTMLReader implements the Reader interface for HTML files (.html, .htm).
+// It keeps the main content of the page (its <article> elements or the <main>
+// element when present), drops scripts, styles and navigation, and renders
+// headings, lists and tables as readable text.
+type HTMLReader struct{}
+
+// NewHTMLReader creates a new HTMLReader.
+func NewHTMLReader() *HTMLReader {
+	return &HTMLReader{}
+}
+
+// Extract reads an HTML file and returns the main content as plain text.
+func (r *HTMLReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads HTML from ra and returns the main content as plain text.
+func (r *HTMLReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	content, err := readAllAt(ra, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read html file %s: %w", name, err)
+	}
+	return renderLines(htmlContentLines(parseHTML(DecodeText(content)))), nil
+}
+
+// ExtractSections reads an HTML file and returns its main content grouped by heading.
+func (r *HTMLReader) ExtractSections(filePath string) ([]types.Section, error) {
+	doc, err := r.parseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return groupSections(htmlContentLines(doc)), nil
+}
+
+// ExtractMetadata returns the page <title> and the description, author and
+// keywords <meta> tags, plus the document language when declared.
+func (r *HTMLReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	doc, err := r.parseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := make(map[string]string)
+	if title := findHTML(doc, func(n *html.Node) bool { return isElement(n, "title") }); title != nil {
+		if t := inlineText(title); t != "" {
+			meta["title"] = t
+		}
+	}
+	if meta["title"] == "" {
+		if h1 := findHTML(doc, func(n *html.Node) bool { return isElement(n, "h1") }); h1 != nil {
+			if t := inlineText(h1); t != "" {
+				meta["title"] = t
+			}
+		}
+	}
+	walkHTML(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		switch n.Data {
+		case "html":
+			if lang := htmlAttr(n, "lang"); lang != "" {
+				meta["language"] = lang
+			}
+		case "meta":
+			name := strings.ToLower(htmlAttr(n, "name"))
+			switch name {
+			case "description", "author", "keywords":
+				if content := strings.TrimSpace(htmlAttr(n, "content")); content != "" {
+					meta[name] = content
+				}
+			}
+		}
+	})
+	return meta, nil
+}
+
+func (r *HTMLReader) parseFile(filePath string) (*html.Node, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read html file %s: %w", filePath, err)
+	}
+	return parseHTML(DecodeText(content)), nil
+}
+
+// SniffHTML matches content that starts with an HTML doctype or <html> element.
+func SniffHTML(ra io.ReaderAt, size int64) bool {
+	head := bytes.ToLower(readHead(ra, size, 1024))
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimSpace(head)
+	return bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<html"))
+}
+
+// parseHTML parses HTML source the way browsers do, recovering from
+// unclosed and mismatched tags.
+func parseHTML(src string) *html.Node {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		// html.Parse only fails when reading fails, which a strings.Reader does not.
+		return &html.Node{Type: html.DocumentNode}
+	}
+	return doc
+}
+
+// walkHTML calls fn for n and every node below it in document order.
+func walkHTML(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, fn)
+	}
+}
+
+// findHTML returns the first node in document order matching fn.
+func findHTML(n *html.Node, fn func(*html.Node) bool) *html.Node {
+	if fn(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findHTML(c, fn); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAllHTML returns the outermost nodes matching fn, in document order.
+func findAllHTML(n *html.Node, fn func(*html.Node) bool) []*html.Node {
+	if fn(n) {
+		return []*html.Node{n}
+	}
+	var found []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findAllHTML(c, fn)...)
+	}
+	return found
+}
+
+// isElement reports whether n is an element with the given lower-case name.
+func isElement(n *html.Node, tag string) bool {
+	return n.Type == html.ElementNode && n.Data == tag
+}
+
+// htmlAttr returns the value of the attribute key of n, or "".
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+var (
+	// htmlSkip elements never contribute to the extracted text.
+	htmlSkip = map[string]bool{
+		"head": true, "script": true, "style": true, "noscript": true, "template": true, "nav": true,
+		"aside": true, "form": true, "button": true, "select": true, "svg": true, "iframe": true,
+	}
+	// htmlChrome elements are page furniture outside of an <article> or <main>.
+	htmlChrome = map[string]bool{"header": true, "footer": true}
+	htmlBlock  = map[string]bool{
+		"p": true, "div": true, "section": true, "article": true, "main": true, "blockquote": true,
+		"dl": true, "dt": true, "dd": true, "figure": true, "figcaption": true, "address": true,
+		"hr": true, "header": true, "footer": true, "details": true, "summary": true, "body": true,
+	}
+	htmlHeadingLevel = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+)
+
+// htmlContentLines selects the main content of a document and renders it as
+// lines. The content is every <article> element in document order, or else
+// the <main> element, or else the whole page without its header and footer.
+func htmlContentLines(doc *html.Node) []textLine {
+	content := findAllHTML(doc, func(n *html.Node) bool { return isElement(n, "article") })
+	if len(content) == 0 {
+		if main := findHTML(doc, func(n *html.Node) bool {
+			return isElement(n, "main") || (n.Type == html.ElementNode && htmlAttr(n, "role") == "main")
+		}); main != nil {
+			content = []*html.Node{main}
+		}
+	}
+	r := &htmlRenderer{}
+	if len(content) == 0 {
+		content = []*html.Node{doc}
+		r.skipChrome = true
+	}
+	for _, n := range content {
+		r.render(n, 0)
+		r.paragraph()
+	}
+	r.flush()
+	if n := len(r.lines); n > 0 && r.lines[n-1].text == "" {
+		r.lines = r.lines[:n-1]
+	}
+	return r.lines
+}
+
+// htmlRenderer turns a node tree into text lines, keeping block structure.
+type htmlRenderer struct {
+	lines      []textLine
+	line       strings.Builder
+	prefix     string // list indentation and marker for the pending line
+	skipChrome bool
+}
+
+func (r *htmlRenderer) flush() {
+	if text := strings.Join(strings.Fields(r.line.String()), " "); text != "" {
+		r.lines = append(r.lines, textLine{text: r.prefix + text})
+	}
+	r.line.Reset()
+	r.prefix = ""
+}
+
+// paragraph ends the current line and leaves a blank line after it.
+func (r *htmlRenderer) paragraph() {
+	r.flush()
+	if n := len(r.lines); n > 0 && r.lines[n-1].text != "" {
+		r.lines = append(r.lines, textLine{})
+	}
+}
+
+func (r *htmlRenderer) render(n *html.Node, listDepth int) {
+	switch n.Type {
+	case html.TextNode:
+		r.line.WriteString(n.Data)
+		return
+	case html.DocumentNode:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.render(c, listDepth)
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if htmlSkip[n.Data] || (r.skipChrome && htmlChrome[n.Data]) {
+		return
+	}
+
+	if level, ok := htmlHeadingLevel[n.Data]; ok {
+		r.paragraph()
+		if text := inlineText(n); text != "" {
+			r.lines = append(r.lines, textLine{text: text, level: level})
+		}
+		return
+	}
+
+	switch n.Data {
+	case "br":
+		r.flush()
+	case "pre":
+		r.paragraph()
+		for _, l := range strings.Split(strings.Trim(rawText(n), "\n"), "\n") {
+			r.lines = append(r.lines, textLine{text: strings.TrimRight(l, " \t\r")})
+		}
+		r.paragraph()
+	case "ul", "ol":
+		r.flush()
+		item := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if !isElement(c, "li") {
+				r.render(c, listDepth)
+				continue
+			}
+			item++
+			r.flush()
+			marker := "- "
+			if n.Data == "ol" {
+				marker = fmt.Sprintf("%d. ", item)
+			}
+			r.prefix = strings.Repeat("  ", listDepth) + marker
+			for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+				r.render(cc, listDepth+1)
+			}
+			r.flush()
+		}
+		if listDepth == 0 {
+			r.paragraph()
+		}
+	case "table":
+		r.paragraph()
+		r.renderTable(n)
+		r.paragraph()
+	case "img":
+		if alt := strings.TrimSpace(htmlAttr(n, "alt")); alt != "" {
+			r.line.WriteString(" " + alt + " ")
+		}
+	default:
+		block := htmlBlock[n.Data]
+		if block {
+			r.flush()
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			r.render(c, listDepth)
+		}
+		if n.Data == "p" || n.Data == "blockquote" {
+			r.paragraph()
+		} else if block {
+			r.flush()
+		}
+	}
+}
+
+// renderTable writes each table row as one line with cells separated by " | ".
+func (r *htmlRenderer) renderTable(table *html.Node) {
+	var rows func(n *html.Node)
+	rows = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "caption":
+				if text := inlineText(c); text != "" {
+					r.lines = append(r.lines, textLine{text: text})
+				}
+			case "thead", "tbody", "tfoot":
+				rows(c)
+			case "tr":
+				var cells []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if isElement(cell, "td") || isElement(cell, "th") {
+						cells = append(cells, inlineText(cell))
+					}
+				}
+				if strings.TrimSpace(strings.Join(cells, "")) != "" {
+					r.lines = append(r.lines, textLine{text: strings.Join(cells, " | ")})
+				}
+			}
+		}
+	}
+	rows(table)
+}
+
+// inlineText returns the visible text under n with whitespace collapsed.
+func inlineText(n *html.Node) string {
+	var sb strings.Builder
+	walkHTML(n, func(c *html.Node) {
+		switch {
+		case c.Type == html.TextNode && !hasSkippedAncestor(c):
+			sb.WriteString(c.Data)
+		case c.Type == html.ElementNode && (c.Data == "br" || htmlBlock[c.Data] || c.Data == "li"):
+			sb.WriteString(" ")
+		}
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// rawText returns the text under n with whitespace preserved, for <pre> blocks.
+func rawText(n *html.Node) string {
+	var sb strings.Builder
+	walkHTML(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		} else if isElement(c, "br") {
+			sb.WriteString("\n")
+		}
+	})
+	return sb.String()
+}
+
+func hasSkippedAncestor(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && htmlSkip[p.Data] && p.Data != "head" {
+			return true
+		}
+	}
+	return false
+}
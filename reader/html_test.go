@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+func extractHTML(t *testing.T, src string) string {
+	t.Helper()
+	got, err := NewHTMLReader().ExtractFrom(strings.NewReader(src), int64(len(src)), "page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestHTMLExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "boilerplate dropped",
+			src: `<html><head><title>T</title><style>p{}</style><script>var x = "<p>";</script></head>
+<body><header>Site header</header><nav><a href="/">Home</a></nav>
+<p>Body &amp; soul.</p><footer>Copyright</footer></body></html>`,
+			want: "Body & soul.",
+		},
+		{
+			name: "main element",
+			src:  `<body><div>Sidebar</div><main><h1>Title</h1><p>Main text.</p></main></body>`,
+			want: "Title\nMain text.",
+		},
+		{
+			name: "every article kept",
+			src: `<body><p>Teaser</p><article><h2>First</h2><p>One.</p></article>
+<div><article><h2>Second</h2><p>Two.</p></article></div></body>`,
+			want: "First\nOne.\n\nSecond\nTwo.",
+		},
+		{
+			name: "nested article counted once",
+			src:  `<article><p>Outer.</p><article><p>Inner.</p></article></article>`,
+			want: "Outer.\n\nInner.",
+		},
+		{
+			name: "lists",
+			src:  `<ul><li>one<li>two<ol><li>a</li><li>b</li></ol></ul>`,
+			want: "- one\n- two\n  1. a\n  2. b",
+		},
+		{
+			name: "table",
+			src:  `<table><caption>Prices</caption><tr><th>Item<th>Cost<tr><td>Tea<td>2</table>`,
+			want: "Prices\nItem | Cost\nTea | 2",
+		},
+		{
+			name: "pre keeps whitespace",
+			src:  "<pre>  indented\n    more</pre>",
+			want: "indented\n    more",
+		},
+		{
+			name: "unclosed paragraphs and image alt",
+			src:  `<p>First <img alt="chart"> here<p>Second<br>line`,
+			want: "First chart here\n\nSecond\nline",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractHTML(t, tt.src); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLExtractSections(t *testing.T) {
+	src := `<html><body><h1>Guide</h1><p>Intro.</p><h2>Install</h2><p>Run it.</p><h2>Use</h2><p>Click.</p></body></html>`
+	path := writeFile(t, "guide.html", []byte(src))
+	sections, err := NewHTMLReader().ExtractSections(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Section{
+		{Headings: []string{"Guide"}, Text: "Intro."},
+		{Headings: []string{"Guide", "Install"}, Text: "Run it."},
+		{Headings: []string{"Guide", "Use"}, Text: "Click."},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("got %+v, want %+v", sections, want)
+	}
+}
+
+func TestHTMLExtractMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{
+			name: "head tags",
+			src: `<html lang="en"><head><title> Page  Title </title>
+<meta name="Description" content="About it"><meta name="author" content="Ann"></head><body></body></html>`,
+			want: map[string]string{"title": "Page Title", "description": "About it", "author": "Ann", "language": "en"},
+		},
+		{
+			name: "title from h1",
+			src:  `<body><h1>Heading</h1></body>`,
+			want: map[string]string{"title": "Heading"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := NewHTMLReader().ExtractMetadata(writeFile(t, "page.html", []byte(tt.src)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(meta, tt.want) {
+				t.Errorf("got %v, want %v", meta, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniffHTML(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"<!DOCTYPE html><html>", true},
+		{"\xef\xbb\xbf  <html lang=en>", true},
+		{"<p>fragment</p>", false},
+		{"plain text", false},
+	}
+	for _, tt := range tests {
+		if got := SniffHTML(strings.NewReader(tt.src), int64(len(tt.src))); got != tt.want {
+			t.Errorf("SniffHTML(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,130 @@
 package reader
 
+import (
+	"context"
+	"io"
+
+	"github.com/Ashank007/docai/types"
+)
+
 type Reader interface {
 	Extract(path string) (string, error)
 }
+
+// PageReader is implemented by readers whose formats have page boundaries.
+// The returned slice holds one entry per page, in page order.
+type PageReader interface {
+	ExtractPages(path string) ([]string, error)
+}
+
+// SectionReader is implemented by readers that can recover a document's heading structure.
+// Sections are returned in document order.
+type SectionReader interface {
+	ExtractSections(path string) ([]types.Section, error)
+}
+
+// MetadataReader is implemented by readers that can extract document-level
+// metadata such as the title or author. Keys are lower-case, e.g. "title".
+type MetadataReader interface {
+	ExtractMetadata(path string) (map[string]string, error)
+}
+
+// DocumentReader is implemented by readers whose files hold several documents,
+// such as mailboxes. Each document should be indexed under its own name.
+type DocumentReader interface {
+	ExtractDocuments(path string) ([]types.Document, error)
+}
+
+// StreamReader is implemented by readers that can extract text from content
+// that is not a file on disk, such as an upload, stdin or a blob in memory.
+// name is used for error messages and, where it matters, its extension.
+type StreamReader interface {
+	ExtractFrom(r io.ReaderAt, size int64, name string) (string, error)
+}
+
+// ContextReader is implemented by readers that can stop part-way through a
+// large file, such as an archive, when ctx is cancelled.
+type ContextReader interface {
+	ExtractContext(ctx context.Context, path string) (string, error)
+}
+
+// ContextDocumentReader is a DocumentReader that can be cancelled through ctx.
+type ContextDocumentReader interface {
+	ExtractDocumentsContext(ctx context.Context, path string) ([]types.Document, error)
+}
+
+// ContextPageReader is a PageReader that can be cancelled through ctx.
+type ContextPageReader interface {
+	ExtractPagesContext(ctx context.Context, path string) ([]string, error)
+}
+
+// ContextSectionReader is a SectionReader that can be cancelled through ctx.
+type ContextSectionReader interface {
+	ExtractSectionsContext(ctx context.Context, path string) ([]types.Section, error)
+}
+
+// ContextStreamReader is a StreamReader that can be cancelled through ctx.
+type ContextStreamReader interface {
+	ExtractFromContext(ctx context.Context, r io.ReaderAt, size int64, name string) (string, error)
+}
+
+// ExtractContext extracts the text of path with rd, bound to ctx when rd is a
+// ContextReader. Other readers are not started once ctx is done.
+func ExtractContext(ctx context.Context, rd Reader, path string) (string, error) {
+	if cr, ok := rd.(ContextReader); ok {
+		return cr.ExtractContext(ctx, path)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return rd.Extract(path)
+}
+
+// ExtractDocumentsContext extracts the documents of path with dr, bound to
+// ctx when dr is a ContextDocumentReader.
+func ExtractDocumentsContext(ctx context.Context, dr DocumentReader, path string) ([]types.Document, error) {
+	if cr, ok := dr.(ContextDocumentReader); ok {
+		return cr.ExtractDocumentsContext(ctx, path)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return dr.ExtractDocuments(path)
+}
+
+// ExtractPagesContext extracts the pages of path with pr, bound to ctx when
+// pr is a ContextPageReader. Other readers are not started once ctx is done.
+func ExtractPagesContext(ctx context.Context, pr PageReader, path string) ([]string, error) {
+	if cr, ok := pr.(ContextPageReader); ok {
+		return cr.ExtractPagesContext(ctx, path)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pr.ExtractPages(path)
+}
+
+// ExtractSectionsContext extracts the sections of path with sr, bound to ctx
+// when sr is a ContextSectionReader. Other readers are not started once ctx
+// is done.
+func ExtractSectionsContext(ctx context.Context, sr SectionReader, path string) ([]types.Section, error) {
+	if cr, ok := sr.(ContextSectionReader); ok {
+		return cr.ExtractSectionsContext(ctx, path)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return sr.ExtractSections(path)
+}
+
+// ExtractStreamContext extracts the text of the content in ra with sr, bound
+// to ctx when sr is a ContextStreamReader.
+func ExtractStreamContext(ctx context.Context, sr StreamReader, ra io.ReaderAt, size int64, name string) (string, error) {
+	if cr, ok := sr.(ContextStreamReader); ok {
+		return cr.ExtractFromContext(ctx, ra, size, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return sr.ExtractFrom(ra, size, name)
+}
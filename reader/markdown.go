@@ -0,0 +1,202 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// MarkdownReader implements the Reader interface for Markdown files (.md).
+// It strips Markdown syntax (heading markers, link targets, emphasis, code
+// fences) and can also return the text grouped by heading.
+type MarkdownReader struct{}
+
+// NewMarkdownReader creates a new MarkdownReader.
+func NewMarkdownReader() *MarkdownReader {
+	return &MarkdownReader{}
+}
+
+// Extract reads a Markdown file and returns it as clean plain text.
+// Heading titles are kept as their own lines.
+func (r *MarkdownReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads Markdown from ra and returns it as clean plain text.
+func (r *MarkdownReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	content, err := readAllAt(ra, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read markdown file %s: %w", name, err)
+	}
+	return renderLines(parseMarkdown(DecodeText(content))), nil
+}
+
+// ExtractSections reads a Markdown file and returns one section per block of
+// body text, each with the path of headings it sits under.
+func (r *MarkdownReader) ExtractSections(filePath string) ([]types.Section, error) {
+	lines, err := r.parseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return groupSections(lines), nil
+}
+
+func (r *MarkdownReader) parseFile(filePath string) ([]textLine, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read markdown file %s: %w", filePath, err)
+	}
+	return parseMarkdown(DecodeText(content)), nil
+}
+
+var (
+	mdATXHeading  = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdSetextH1    = regexp.MustCompile(`^=+\s*$`)
+	mdSetextH2    = regexp.MustCompile(`^-+\s*$`)
+	mdRule        = regexp.MustCompile(`^(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	mdLinkDef     = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+	mdTableSep    = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	mdBlockquote  = regexp.MustCompile(`^\s*(?:>\s?)+`)
+	mdBullet      = regexp.MustCompile(`^(\s*)[*+-]\s+`)
+	mdCodeSpan    = regexp.MustCompile("`+([^`]+?)`+")
+	mdImage       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink        = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdRefLink     = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	mdAutoLink    = regexp.MustCompile(`<((?:https?|ftp|mailto):[^>\s]+)>`)
+	mdHTMLTag     = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdBold        = regexp.MustCompile(`\*\*(\S(?:[^*]*?\S)?)\*\*`)
+	mdBoldUnder   = regexp.MustCompile(`__(\S(?:[^_]*?\S)?)__`)
+	mdItalic      = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	mdItalicUnder = regexp.MustCompile(`(^|[^\w])_(\S(?:[^_]*?\S)?)_([^\w]|$)`)
+	mdStrike      = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	mdEscape      = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!|>~])")
+)
+
+// mdEscapeBase is the start of a private-use range that stands in for
+// backslash-escaped ASCII characters while inline syntax is stripped.
+const mdEscapeBase = 0xE000
+
+// parseMarkdown turns Markdown source into cleaned lines, marking headings.
+func parseMarkdown(src string) []textLine {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := stripFrontMatter(strings.Split(src, "\n"))
+
+	var out []textLine
+	fence := "" // opening fence marker while inside a fenced code block
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			out = append(out, textLine{text: line})
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if m := mdATXHeading.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, textLine{text: cleanInline(m[2]), level: len(m[1])})
+			continue
+		}
+
+		// A single-line paragraph underlined with === or --- is a setext heading.
+		prevBlank := i == 0 || strings.TrimSpace(lines[i-1]) == ""
+		if trimmed != "" && prevBlank && i+1 < len(lines) && !mdBullet.MatchString(line) {
+			next := strings.TrimSpace(lines[i+1])
+			if mdSetextH1.MatchString(next) {
+				out = append(out, textLine{text: cleanInline(trimmed), level: 1})
+				i++
+				continue
+			}
+			if mdSetextH2.MatchString(next) {
+				out = append(out, textLine{text: cleanInline(trimmed), level: 2})
+				i++
+				continue
+			}
+		}
+
+		if mdRule.MatchString(trimmed) || mdLinkDef.MatchString(trimmed) {
+			out = append(out, textLine{})
+			continue
+		}
+
+		line = mdBlockquote.ReplaceAllString(line, "")
+		trimmed = strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "|") {
+			if mdTableSep.MatchString(trimmed) {
+				continue
+			}
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			for j, c := range cells {
+				cells[j] = cleanInline(strings.TrimSpace(c))
+			}
+			out = append(out, textLine{text: strings.Join(cells, " | ")})
+			continue
+		}
+
+		line = mdBullet.ReplaceAllString(line, "$1- ")
+		out = append(out, textLine{text: cleanInline(strings.TrimRight(line, " \t"))})
+	}
+	return out
+}
+
+// stripFrontMatter drops a leading YAML front matter block delimited by --- lines.
+func stripFrontMatter(lines []string) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if t == "---" || t == "..." {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
+
+// cleanInline removes inline Markdown syntax, leaving code spans untouched.
+func cleanInline(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range mdCodeSpan.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(cleanInlineText(s[last:m[0]]))
+		sb.WriteString(s[m[2]:m[3]])
+		last = m[1]
+	}
+	sb.WriteString(cleanInlineText(s[last:]))
+	return sb.String()
+}
+
+func cleanInlineText(s string) string {
+	// Hide backslash-escaped characters from the syntax patterns below.
+	s = mdEscape.ReplaceAllStringFunc(s, func(esc string) string {
+		return string(rune(mdEscapeBase) + rune(esc[1]))
+	})
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdRefLink.ReplaceAllString(s, "$1")
+	s = mdAutoLink.ReplaceAllString(s, "$1")
+	s = mdHTMLTag.ReplaceAllString(s, "")
+	s = mdBold.ReplaceAllString(s, "$1")
+	s = mdBoldUnder.ReplaceAllString(s, "$1")
+	s = mdItalic.ReplaceAllString(s, "$1")
+	s = mdItalicUnder.ReplaceAllString(s, "$1$2$3")
+	s = mdStrike.ReplaceAllString(s, "$1")
+	return strings.Map(func(r rune) rune {
+		if r >= mdEscapeBase && r < mdEscapeBase+128 {
+			return r - mdEscapeBase
+		}
+		return r
+	}, s)
+}
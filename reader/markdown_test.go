@@ -0,0 +1,73 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+func TestMarkdownExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"atx heading", "# Title #\nBody", "Title\nBody"},
+		{"setext headings", "Title\n=====\n\nSub\n---\n\nBody", "Title\n\nSub\n\nBody"},
+		{"inline syntax", "Some **bold**, *italic*, ~~gone~~ and [a link](http://x.y).", "Some bold, italic, gone and a link."},
+		{"image alt text", "![a cat](cat.png)", "a cat"},
+		{"code span kept", "Use `*ptr` here", "Use *ptr here"},
+		{"escapes", `\*not italic\*`, "*not italic*"},
+		{"fenced code", "```go\nx := *y\n```", "x := *y"},
+		{"front matter", "---\ntitle: x\n---\nBody", "Body"},
+		{"table", "| a | b |\n|---|---|\n| 1 | **2** |", "a | b\n1 | 2"},
+		{"bullets and quotes", "* one\n+ two\n> quoted", "- one\n- two\nquoted"},
+		{"link definition dropped", "Text\n\n[ref]: http://example.com", "Text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMarkdownReader().ExtractFrom(strings.NewReader(tt.src), int64(len(tt.src)), "doc.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownExtractSections(t *testing.T) {
+	src := `# Guide
+
+Intro text.
+
+## Install
+
+Run the installer.
+
+### Linux
+
+Use the package.
+
+## Usage
+
+Start it.
+`
+	path := writeFile(t, "guide.md", []byte(src))
+	sections, err := NewMarkdownReader().ExtractSections(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Section{
+		{Headings: []string{"Guide"}, Text: "Intro text."},
+		{Headings: []string{"Guide", "Install"}, Text: "Run the installer."},
+		{Headings: []string{"Guide", "Install", "Linux"}, Text: "Use the package."},
+		{Headings: []string{"Guide", "Usage"}, Text: "Start it."},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("got %+v, want %+v", sections, want)
+	}
+}
@@ -0,0 +1,40 @@
+package reader
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText is the cleanup stage that extracted text goes through before
+// it is chunked. It replaces invalid UTF-8, converts CRLF, CR and other line
+// and page separators to LF, drops control characters other than tab and
+// newline as well as stray byte order marks, and applies Unicode NFC
+// normalization so that "é" is stored the same way whichever way it was typed.
+func NormalizeText(s string) string {
+	s = strings.ToValidUTF8(s, "�")
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == '\r':
+			if i < len(s) && s[i] == '\n' {
+				i++
+			}
+			sb.WriteByte('\n')
+		case r == '\n' || r == '\t':
+			sb.WriteRune(r)
+		case r == '\f' || r == '\v' || r == 0x85 || r == 0x2028 || r == 0x2029:
+			sb.WriteByte('\n')
+		case r < 0x20 || (r >= 0x7F && r <= 0x9F) || r == 0xFEFF:
+			// Control characters and byte order marks carry no text.
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(sb.String())
+}
@@ -0,0 +1,24 @@
+package reader
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"crlf and cr", "a\r\nb\rc", "a\nb\nc"},
+		{"separators", "a\fb\u2028c\u0085d", "a\nb\nc\nd"},
+		{"controls and bom", "\ufeffa\x00b\x1bc\tz", "abc\tz"},
+		{"invalid utf-8", "a\xffb", "a\ufffdb"},
+		{"nfc compose", "e\u0301", "\u00e9"},
+		{"nfc reorder", "a\u0323\u0302", "\u1ead"},
+		{"hangul", "\u1100\u1161\u11a8", "\uac01"},
+		{"singleton", "\u212b", "\u00c5"},
+		{"already nfc", "d\u00e9j\u00e0 vu", "d\u00e9j\u00e0 vu"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.in); got != tt.want {
+			t.Errorf("%s: NormalizeText(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
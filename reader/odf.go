@@ -0,0 +1,324 @@
+package reader
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// ODTReader implements the Reader and SectionReader interfaces for
+// OpenDocument text files (.odt). Headings come from <text:h> outline levels.
+type ODTReader struct{}
+
+// NewODTReader creates a new ODTReader.
+func NewODTReader() *ODTReader {
+	return &ODTReader{}
+}
+
+// Extract reads the content of an .odt file and returns it as plain text.
+func (r *ODTReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads an OpenDocument text from ra and returns it as plain text.
+func (r *ODTReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	lines, err := readODFContent(ra, size, name, "text")
+	if err != nil {
+		return "", err
+	}
+	return renderLines(lines), nil
+}
+
+// ExtractSections reads an .odt file and returns its text grouped by heading.
+func (r *ODTReader) ExtractSections(filePath string) ([]types.Section, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readODFContent(f, size, filePath, "text")
+	if err != nil {
+		return nil, err
+	}
+	return groupSections(lines), nil
+}
+
+// ODPReader implements the Reader and PageReader interfaces for OpenDocument
+// presentations (.odp). Each slide is one page, followed by its speaker notes.
+type ODPReader struct{}
+
+// NewODPReader creates a new ODPReader.
+func NewODPReader() *ODPReader {
+	return &ODPReader{}
+}
+
+// Extract returns the text of every slide, each introduced by its slide number.
+func (r *ODPReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a presentation from ra and returns the text of every slide, like Extract.
+func (r *ODPReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	slides, err := odpPages(ra, size, name)
+	if err != nil {
+		return "", err
+	}
+	return slidesText(slides), nil
+}
+
+// ExtractPages returns one page per <draw:page>, in presentation order.
+func (r *ODPReader) ExtractPages(filePath string) ([]string, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return odpPages(f, size, filePath)
+}
+
+func odpPages(ra io.ReaderAt, size int64, name string) ([]string, error) {
+	rc, err := openODFContent(ra, size, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var pages []string
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return pages, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'content.xml' in %s: %w", name, err)
+		}
+		if se, ok := token.(xml.StartElement); ok && se.Name.Local == "page" {
+			lines, err := renderODFBlocks(decoder, "page", 0)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing 'content.xml' in %s: %w", name, err)
+			}
+			pages = append(pages, renderLines(lines))
+		}
+	}
+}
+
+// openODFContent opens the content.xml part of an OpenDocument file.
+func openODFContent(ra io.ReaderAt, size int64, name string) (io.ReadCloser, error) {
+	parts, err := zipParts(ra, size, name, "OpenDocument")
+	if err != nil {
+		return nil, err
+	}
+	file, ok := parts["content.xml"]
+	if !ok {
+		return nil, fmt.Errorf("'content.xml' not found in %s", name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open 'content.xml' in %s: %w", name, err)
+	}
+	return rc, nil
+}
+
+// readODFContent renders the <office:{body}> element of content.xml as lines.
+func readODFContent(ra io.ReaderAt, size int64, name, body string) ([]textLine, error) {
+	rc, err := openODFContent(ra, size, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'content.xml' in %s: %w", name, err)
+		}
+		if se, ok := token.(xml.StartElement); ok && se.Name.Local == body && se.Name.Space == odfOfficeNS {
+			lines, err := renderODFBlocks(decoder, body, 0)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing 'content.xml' in %s: %w", name, err)
+			}
+			return lines, nil
+		}
+	}
+}
+
+const odfOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+
+// renderODFBlocks reads paragraphs, headings, lists, tables and slide notes
+// until the end of the element named end. listDepth > 0 marks list items.
+func renderODFBlocks(decoder *xml.Decoder, end string, listDepth int) ([]textLine, error) {
+	var lines []textLine
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "p", "h":
+				text, err := renderODFParagraph(decoder, se.Name.Local)
+				if err != nil {
+					return nil, err
+				}
+				text = strings.TrimSpace(text)
+				if text == "" {
+					continue
+				}
+				line := textLine{text: text}
+				if se.Name.Local == "h" {
+					line.level = 1
+					if lvl, err := strconv.Atoi(xmlAttr(se, "outline-level")); err == nil && lvl > 0 {
+						line.level = lvl
+					}
+				} else if listDepth > 0 {
+					line.text = strings.Repeat("  ", listDepth-1) + "- " + text
+				}
+				lines = append(lines, line)
+			case "list":
+				items, err := renderODFBlocks(decoder, "list", listDepth+1)
+				if err != nil {
+					return nil, err
+				}
+				lines = append(lines, items...)
+			case "table":
+				rows, err := renderODFTable(decoder)
+				if err != nil {
+					return nil, err
+				}
+				lines = append(lines, textLine{})
+				lines = append(lines, rows...)
+				lines = append(lines, textLine{})
+			case "notes":
+				notes, err := renderODFBlocks(decoder, "notes", 0)
+				if err != nil {
+					return nil, err
+				}
+				if len(notes) > 0 {
+					lines = append(lines, textLine{}, textLine{text: "Notes:"})
+					lines = append(lines, notes...)
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == end {
+				return lines, nil
+			}
+		}
+	}
+}
+
+// renderODFParagraph reads a <text:p> or <text:h> and returns its text.
+// Footnotes and comments are kept inline in brackets.
+func renderODFParagraph(decoder *xml.Decoder, end string) (string, error) {
+	var sb strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch se := token.(type) {
+		case xml.CharData:
+			sb.Write(se)
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "s":
+				count := 1
+				if c, err := strconv.Atoi(xmlAttr(se, "c")); err == nil && c > 0 {
+					count = c
+				}
+				sb.WriteString(strings.Repeat(" ", count))
+			case "tab":
+				sb.WriteString("\t")
+			case "line-break":
+				sb.WriteString("\n")
+			case "note-citation", "creator", "date":
+				if err := decoder.Skip(); err != nil {
+					return "", err
+				}
+			case "note-body", "annotation":
+				label := "footnote"
+				if se.Name.Local == "annotation" {
+					label = "comment"
+				}
+				inner, err := renderODFBlocks(decoder, se.Name.Local, 0)
+				if err != nil {
+					return "", err
+				}
+				var texts []string
+				for _, l := range inner {
+					if l.text != "" {
+						texts = append(texts, l.text)
+					}
+				}
+				if len(texts) > 0 {
+					sb.WriteString(fmt.Sprintf(" [%s: %s]", label, strings.Join(texts, " ")))
+				}
+			}
+		case xml.EndElement:
+			if se.Name.Local == end {
+				return sb.String(), nil
+			}
+		}
+	}
+}
+
+// renderODFTable reads a <table:table> and returns one " | "-separated line per row.
+func renderODFTable(decoder *xml.Decoder) ([]textLine, error) {
+	var lines []textLine
+	var row []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "table-row":
+				row = nil
+			case "table-cell":
+				cell, err := renderODFBlocks(decoder, "table-cell", 0)
+				if err != nil {
+					return nil, err
+				}
+				var texts []string
+				for _, l := range cell {
+					if l.text != "" {
+						texts = append(texts, strings.TrimSpace(l.text))
+					}
+				}
+				row = append(row, strings.Join(texts, " "))
+			case "covered-table-cell":
+				row = append(row, "")
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			switch se.Name.Local {
+			case "table-row":
+				// Spreadsheet-style tables repeat empty trailing cells; drop them.
+				for len(row) > 0 && row[len(row)-1] == "" {
+					row = row[:len(row)-1]
+				}
+				if len(row) > 0 {
+					lines = append(lines, textLine{text: strings.Join(row, " | ")})
+				}
+			case "table":
+				return lines, nil
+			}
+		}
+	}
+}
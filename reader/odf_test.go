@@ -0,0 +1,75 @@
+package reader
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+const odfNS = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+xmlns:presentation="urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"
+xmlns:dc="http://purl.org/dc/elements/1.1/"`
+
+func odfFile(t *testing.T, mimetype, body string) []byte {
+	return zipBytes(t,
+		"mimetype", mimetype,
+		"content.xml", `<office:document-content `+odfNS+`><office:body>`+body+`</office:body></office:document-content>`,
+	)
+}
+
+func TestODTExtract(t *testing.T) {
+	body := `<office:text>
+<text:h text:outline-level="1">Report</text:h>
+<text:p>Two<text:s text:c="2"/>spaces<text:note><text:note-citation>1</text:note-citation><text:note-body><text:p>A note.</text:p></text:note-body></text:note>.</text:p>
+<text:p>Check<office:annotation><dc:creator>Ann</dc:creator><text:p>Why?</text:p></office:annotation></text:p>
+<text:h text:outline-level="2">Items</text:h>
+<text:list><text:list-item><text:p>one</text:p><text:list><text:list-item><text:p>nested</text:p></text:list-item></text:list></text:list-item></text:list>
+<table:table><table:table-row><table:table-cell><text:p>a</text:p></table:table-cell><table:covered-table-cell/><table:table-cell><text:p>b</text:p></table:table-cell><table:table-cell/></table:table-row></table:table>
+</office:text>`
+	file := odfFile(t, "application/vnd.oasis.opendocument.text", body)
+
+	got, err := NewODTReader().ExtractFrom(bytes.NewReader(file), int64(len(file)), "doc.odt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Report\nTwo  spaces [footnote: A note.].\nCheck [comment: Why?]\n\nItems\n- one\n  - nested\n\na |  | b"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	sections, err := NewODTReader().ExtractSections(writeFile(t, "doc.odt", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSections := []types.Section{
+		{Headings: []string{"Report"}, Text: "Two  spaces [footnote: A note.].\nCheck [comment: Why?]"},
+		{Headings: []string{"Report", "Items"}, Text: "- one\n  - nested\n\na |  | b"},
+	}
+	if !reflect.DeepEqual(sections, wantSections) {
+		t.Errorf("got %+v, want %+v", sections, wantSections)
+	}
+}
+
+func TestODPPages(t *testing.T) {
+	body := `<office:presentation>
+<draw:page draw:name="p1"><draw:frame><draw:text-box><text:p>Title slide</text:p></draw:text-box></draw:frame>
+<presentation:notes><draw:frame><draw:text-box><text:p>Speak slowly.</text:p></draw:text-box></draw:frame></presentation:notes></draw:page>
+<draw:page draw:name="p2"></draw:page>
+<draw:page draw:name="p3"><draw:frame><draw:text-box><text:p>End</text:p></draw:text-box></draw:frame></draw:page>
+</office:presentation>`
+	file := odfFile(t, "application/vnd.oasis.opendocument.presentation", body)
+
+	pages, err := odpPages(bytes.NewReader(file), int64(len(file)), "deck.odp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Title slide\n\nNotes:\nSpeak slowly.", "", "End"}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("got %q, want %q", pages, want)
+	}
+}
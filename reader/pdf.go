@@ -2,8 +2,13 @@ package reader
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 	//"os"
 
 	"github.com/ledongthuc/pdf"
@@ -16,11 +21,15 @@ func NewPDFReader() *PDFReader {
 }
 
 func (p *PDFReader) Extract(path string) (string, error) {
-	file, reader, err := pdf.Open(path)
+	return extractFile(p, path)
+}
+
+// ExtractFrom reads a PDF document from ra and returns its plain text.
+func (p *PDFReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	reader, err := pdf.NewReader(ra, size)
 	if err != nil {
-		return "", fmt.Errorf("failed to open PDF: %w", err)
+		return "", fmt.Errorf("failed to open PDF %s: %w", name, err)
 	}
-	defer file.Close()
 
 	var buf bytes.Buffer
 	text, err := reader.GetPlainText()
@@ -34,3 +43,114 @@ func (p *PDFReader) Extract(path string) (string, error) {
 
 	return buf.String(), nil
 }
+
+// ExtractPages returns the plain text of every page, so page i+1 is pages[i].
+func (p *PDFReader) ExtractPages(path string) ([]string, error) {
+	return p.ExtractPagesContext(context.Background(), path)
+}
+
+// ExtractPagesContext is ExtractPages, stopping between pages once ctx is done.
+func (p *PDFReader) ExtractPagesContext(ctx context.Context, path string) ([]string, error) {
+	file, reader, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer file.Close()
+
+	numPages := reader.NumPage()
+	pages := make([]string, 0, numPages)
+	fonts := make(map[string]*pdf.Font) // cache fonts so each charmap is parsed once
+	for i := 1; i <= numPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page := reader.Page(i)
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				f := page.Font(name)
+				fonts[name] = &f
+			}
+		}
+		text, err := page.GetPlainText(fonts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+		pages = append(pages, text)
+	}
+
+	return pages, nil
+}
+
+// pdfInfoKeys maps the standard entries of the document Info dictionary to
+// metadata keys. Other entries are kept under their lower-cased name.
+var pdfInfoKeys = map[string]string{
+	"Title":        "title",
+	"Author":       "author",
+	"Subject":      "subject",
+	"Keywords":     "keywords",
+	"Creator":      "creator",
+	"Producer":     "producer",
+	"CreationDate": "created",
+	"ModDate":      "modified",
+}
+
+// ExtractMetadata returns the text entries of the document Info dictionary,
+// such as title, author and created. Dates are converted to RFC 3339.
+func (p *PDFReader) ExtractMetadata(path string) (map[string]string, error) {
+	file, reader, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer file.Close()
+
+	meta := make(map[string]string)
+	info := reader.Trailer().Key("Info")
+	for _, key := range info.Keys() {
+		value := info.Key(key)
+		if value.Kind() != pdf.String {
+			continue
+		}
+		text := strings.TrimSpace(value.Text())
+		if text == "" {
+			continue
+		}
+		name, ok := pdfInfoKeys[key]
+		if !ok {
+			name = strings.ToLower(key)
+		}
+		if name == "created" || name == "modified" {
+			if t, ok := parsePDFDate(text); ok {
+				text = t.Format(time.RFC3339)
+			}
+		}
+		meta[name] = text
+	}
+	return meta, nil
+}
+
+var pdfDate = regexp.MustCompile(`^(?:D:)?(\d{4})(\d{2})?(\d{2})?(\d{2})?(\d{2})?(\d{2})?(?:([Zz+-])(\d{2})?'?(\d{2})?'?)?$`)
+
+// parsePDFDate parses a PDF date string such as "D:20240131143000+01'00'".
+// Every part after the year is optional.
+func parsePDFDate(s string) (time.Time, bool) {
+	m := pdfDate.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false
+	}
+	num := func(i, def int) int {
+		n := def
+		if v, err := strconv.Atoi(m[i]); err == nil {
+			n = v
+		}
+		return n
+	}
+	loc := time.UTC
+	if m[7] == "+" || m[7] == "-" {
+		offset := num(8, 0)*3600 + num(9, 0)*60
+		if m[7] == "-" {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	return time.Date(num(1, 0), time.Month(num(2, 1)), num(3, 1), num(4, 0), num(5, 0), num(6, 0), 0, loc), true
+}
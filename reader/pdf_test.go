@@ -0,0 +1,134 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildPDF writes a minimal PDF with one page per entry of pages, each
+// showing its text in Helvetica, and an Info dictionary holding info.
+func buildPDF(pages []string, info string) []byte {
+	n := len(pages)
+	// Objects: 1 catalog, 2 page tree, 3 font, 4 info, then a page and its
+	// content stream for every page.
+	var objs []string
+	kids := make([]string, n)
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), n),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< "+info+" >>",
+	)
+	for i, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buf.Bytes()
+}
+
+func TestPDFExtractPages(t *testing.T) {
+	path := writeFile(t, "doc.pdf", buildPDF([]string{"First page", "Second page", "Third page"}, ""))
+
+	pages, err := NewPDFReader().ExtractPages(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"First page", "Second page", "Third page"}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(pages), len(want))
+	}
+	for i := range want {
+		if strings.TrimSpace(pages[i]) != want[i] {
+			t.Errorf("page %d = %q, want %q", i+1, pages[i], want[i])
+		}
+	}
+
+	text, err := NewPDFReader().Extract(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range want {
+		if !strings.Contains(text, w) {
+			t.Errorf("Extract() = %q, missing %q", text, w)
+		}
+	}
+}
+
+func TestPDFExtractPagesContext(t *testing.T) {
+	path := writeFile(t, "doc.pdf", buildPDF([]string{"First page", "Second page"}, ""))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ExtractPagesContext(ctx, NewPDFReader(), path); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestPDFExtractMetadata(t *testing.T) {
+	path := writeFile(t, "doc.pdf", buildPDF([]string{"Body"}, "/Title (Annual Report) /Author (Jane Doe) /CreationDate (D:20240131143000+01'00') /Custom (x)"))
+
+	meta, err := NewPDFReader().ExtractMetadata(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"title":   "Annual Report",
+		"author":  "Jane Doe",
+		"created": "2024-01-31T14:30:00+01:00",
+		"custom":  "x",
+	}
+	for k, v := range want {
+		if meta[k] != v {
+			t.Errorf("meta[%q] = %q, want %q", k, meta[k], v)
+		}
+	}
+}
+
+func TestParsePDFDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"D:20240131143000+01'00'", "2024-01-31T14:30:00+01:00", true},
+		{"D:20240131143000Z", "2024-01-31T14:30:00Z", true},
+		{"D:20240131143000-05'30", "2024-01-31T14:30:00-05:30", true},
+		{"2024", "2024-01-01T00:00:00Z", true},
+		{"D:202403", "2024-03-01T00:00:00Z", true},
+		{"yesterday", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePDFDate(tt.in)
+		if ok != tt.ok {
+			t.Errorf("parsePDFDate(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			continue
+		}
+		if ok && got.Format(time.RFC3339) != tt.want {
+			t.Errorf("parsePDFDate(%q) = %s, want %s", tt.in, got.Format(time.RFC3339), tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package reader
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// PPTXReader implements the Reader, PageReader and MetadataReader interfaces
+// for PowerPoint files (.pptx).
+//
+// Each slide is one page holding the slide text followed by its speaker
+// notes, so chunks record the slide number in Chunk.Page.
+type PPTXReader struct{}
+
+// NewPPTXReader creates a new PPTXReader.
+func NewPPTXReader() *PPTXReader {
+	return &PPTXReader{}
+}
+
+// pptxNotesSkip lists notes-page placeholders that repeat slide furniture
+// rather than holding the speaker's notes.
+var pptxNotesSkip = map[string]bool{"sldImg": true, "sldNum": true, "hdr": true, "ftr": true, "dt": true}
+
+// Extract returns the text of every slide, each introduced by its slide number.
+func (r *PPTXReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a presentation from ra and returns the text of every slide, like Extract.
+func (r *PPTXReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	slides, err := pptxPages(ra, size, name)
+	if err != nil {
+		return "", err
+	}
+	return slidesText(slides), nil
+}
+
+// slidesText joins slide pages, introducing each with its slide number.
+func slidesText(slides []string) string {
+	var sb strings.Builder
+	for i, slide := range slides {
+		if slide == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("Slide %d:\n%s\n\n", i+1, slide))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// ExtractPages returns one page per slide, in presentation order.
+func (r *PPTXReader) ExtractPages(filePath string) ([]string, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return pptxPages(f, size, filePath)
+}
+
+// ExtractMetadata returns the title, author, created and other core
+// properties from docProps/core.xml.
+func (r *PPTXReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	return ooxmlMetadata(filePath, "pptx")
+}
+
+func pptxPages(ra io.ReaderAt, size int64, name string) ([]string, error) {
+	parts, err := zipParts(ra, size, name, "pptx")
+	if err != nil {
+		return nil, err
+	}
+
+	slideParts, err := pptxSlideParts(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]string, 0, len(slideParts))
+	for _, slidePart := range slideParts {
+		file, ok := parts[slidePart]
+		if !ok {
+			return nil, fmt.Errorf("slide '%s' not found in pptx file %s", slidePart, name)
+		}
+		text, err := readDrawingText(file, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		notesPart, err := pptxNotesPart(parts, slidePart)
+		if err != nil {
+			return nil, err
+		}
+		if notesFile, ok := parts[notesPart]; ok {
+			notes, err := readDrawingText(notesFile, pptxNotesSkip)
+			if err != nil {
+				return nil, err
+			}
+			if notes != "" {
+				text = strings.TrimSpace(text + "\n\nNotes:\n" + notes)
+			}
+		}
+		pages = append(pages, text)
+	}
+	return pages, nil
+}
+
+// pptxSlideParts returns the slide part names in presentation order.
+func pptxSlideParts(parts map[string]*zip.File) ([]string, error) {
+	var presentation struct {
+		Slides []struct {
+			Attr []xml.Attr `xml:",any,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := decodeZipXML(parts, "ppt/presentation.xml", &presentation); err != nil {
+		return nil, err
+	}
+	targets, err := relationshipTargets(parts, "ppt/_rels/presentation.xml.rels", "ppt")
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	for _, s := range presentation.Slides {
+		for _, a := range s.Attr {
+			if a.Name.Local == "id" && a.Name.Space != "" {
+				if target, ok := targets[a.Value]; ok {
+					slides = append(slides, target.path)
+				}
+			}
+		}
+	}
+	return slides, nil
+}
+
+// pptxNotesPart returns the notes slide part linked from a slide, or "" if it has none.
+func pptxNotesPart(parts map[string]*zip.File, slidePart string) (string, error) {
+	dir, name := path.Split(slidePart)
+	relsName := path.Join(dir, "_rels", name+".rels")
+	if _, ok := parts[relsName]; !ok {
+		return "", nil
+	}
+	targets, err := relationshipTargets(parts, relsName, strings.TrimSuffix(dir, "/"))
+	if err != nil {
+		return "", err
+	}
+	for _, t := range targets {
+		if strings.HasSuffix(t.relType, "/notesSlide") {
+			return t.path, nil
+		}
+	}
+	return "", nil
+}
+
+// readDrawingText returns the DrawingML text of a slide or notes part: one
+// line per paragraph, and one " | "-separated line per table row. Shapes
+// whose placeholder type is in skip are left out.
+func readDrawingText(file *zip.File, skip map[string]bool) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open '%s' in pptx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	var (
+		lines     []string
+		paragraph strings.Builder
+		cell      strings.Builder
+		row       []string
+		inCell    bool
+		shapes    []string // placeholder type of each open shape
+	)
+	skipping := func() bool {
+		for _, ph := range shapes {
+			if skip[ph] {
+				return true
+			}
+		}
+		return false
+	}
+
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing '%s' in pptx: %w", file.Name, err)
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "sp":
+				shapes = append(shapes, "")
+			case "ph":
+				if len(shapes) > 0 {
+					shapes[len(shapes)-1] = xmlAttr(se, "type")
+				}
+			case "t":
+				var text string
+				if err := decoder.DecodeElement(&text, &se); err != nil {
+					return "", fmt.Errorf("error decoding text element: %w", err)
+				}
+				paragraph.WriteString(text)
+			case "br":
+				paragraph.WriteString("\n")
+			case "tr":
+				row = nil
+			case "tc":
+				inCell = true
+				cell.Reset()
+			}
+		case xml.EndElement:
+			switch se.Name.Local {
+			case "sp":
+				if len(shapes) > 0 {
+					shapes = shapes[:len(shapes)-1]
+				}
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				paragraph.Reset()
+				switch {
+				case text == "" || skipping():
+				case inCell:
+					if cell.Len() > 0 {
+						cell.WriteString(" ")
+					}
+					cell.WriteString(text)
+				default:
+					lines = append(lines, text)
+				}
+			case "tc":
+				inCell = false
+				row = append(row, strings.ReplaceAll(cell.String(), "\n", " "))
+			case "tr":
+				if strings.TrimSpace(strings.Join(row, "")) != "" {
+					lines = append(lines, strings.Join(row, " | "))
+				}
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
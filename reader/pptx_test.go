@@ -0,0 +1,65 @@
+package reader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPPTXPages(t *testing.T) {
+	const a = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+	const relNS = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+	presentation := `<p:presentation ` + a + ` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<p:sldIdLst><p:sldId id="256" r:id="rId3"/><p:sldId id="257" r:id="rId2"/></p:sldIdLst></p:presentation>`
+	rels := `<Relationships ` + relNS + `>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide2.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/></Relationships>`
+	slide1 := `<p:sld ` + a + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>Welcome</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:txBody><a:p><a:r><a:t>First </a:t></a:r><a:r><a:t>point</a:t></a:r></a:p><a:p><a:r><a:t>Line</a:t></a:r><a:br/><a:r><a:t>break</a:t></a:r></a:p></p:txBody></p:sp>
+<p:graphicFrame><a:graphic><a:graphicData><a:tbl>
+<a:tr><a:tc><a:txBody><a:p><a:r><a:t>Q1</a:t></a:r></a:p></a:txBody></a:tc><a:tc><a:txBody><a:p><a:r><a:t>10</a:t></a:r></a:p></a:txBody></a:tc></a:tr>
+</a:tbl></a:graphicData></a:graphic></p:graphicFrame>
+</p:spTree></p:cSld></p:sld>`
+	slide1Rels := `<Relationships ` + relNS + `>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide" Target="../notesSlides/notesSlide1.xml"/></Relationships>`
+	notes := `<p:notes ` + a + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="sldImg"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>thumbnail</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="sldNum"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>1</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="body"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>Say hello.</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:notes>`
+	slide2 := `<p:sld ` + a + `><p:cSld><p:spTree><p:sp><p:txBody><a:p><a:r><a:t>Second slide</a:t></a:r></a:p></p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+
+	file := zipBytes(t,
+		"ppt/presentation.xml", presentation,
+		"ppt/_rels/presentation.xml.rels", rels,
+		"ppt/slides/slide1.xml", slide1,
+		"ppt/slides/_rels/slide1.xml.rels", slide1Rels,
+		"ppt/notesSlides/notesSlide1.xml", notes,
+		"ppt/slides/slide2.xml", slide2,
+	)
+
+	pages, err := pptxPages(bytes.NewReader(file), int64(len(file)), "deck.pptx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Welcome\nFirst point\nLine\nbreak\nQ1 | 10\n\nNotes:\nSay hello.",
+		"Second slide",
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d: %q", len(pages), len(want), pages)
+	}
+	for i := range want {
+		if pages[i] != want[i] {
+			t.Errorf("slide %d = %q, want %q", i+1, pages[i], want[i])
+		}
+	}
+
+	text, err := NewPPTXReader().ExtractFrom(bytes.NewReader(file), int64(len(file)), "deck.pptx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Slide 1:\n" + want[0] + "\n\nSlide 2:\n" + want[1]; text != want {
+		t.Errorf("ExtractFrom() = %q, want %q", text, want)
+	}
+}
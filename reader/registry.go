@@ -0,0 +1,364 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedFormat is returned when no registered Reader can handle a file.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// Sniffer reports whether the content behind ra looks like a format a Reader can handle.
+type Sniffer func(ra io.ReaderAt, size int64) bool
+
+type sniffEntry struct {
+	sniff  Sniffer
+	reader Reader
+}
+
+// Registry picks a Reader for a file by content sniffing and by extension.
+//
+// Lookup order is: content sniffers (magic bytes), then the file extension,
+// then fallback sniffers for formats without a reliable signature (plain text).
+type Registry struct {
+	byExt     map[string]Reader
+	sniffers  []sniffEntry
+	fallbacks []sniffEntry
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byExt: make(map[string]Reader),
+	}
+}
+
+// NewDefaultRegistry creates a Registry with all built-in readers registered.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(NewPDFReader(), SniffPDF, ".pdf")
+	r.Register(NewDocxReader(), SniffZipEntry("word/document.xml"), ".docx")
+	r.Register(NewTextReader(), nil, ".txt", ".text", ".log")
+	r.Register(NewMarkdownReader(), nil, ".md", ".markdown", ".mdown")
+	r.Register(NewHTMLReader(), SniffHTML, ".html", ".htm", ".xhtml")
+	r.Register(NewXLSXReader(), SniffZipEntry("xl/workbook.xml"), ".xlsx")
+	r.Register(NewCSVReader(), nil, ".csv", ".tsv")
+	r.Register(NewPPTXReader(), SniffZipEntry("ppt/presentation.xml"), ".pptx")
+	r.Register(NewODTReader(), SniffZipMimetype("application/vnd.oasis.opendocument.text"), ".odt")
+	r.Register(NewODPReader(), SniffZipMimetype("application/vnd.oasis.opendocument.presentation"), ".odp")
+	r.Register(NewEPUBReader(), SniffZipMimetype("application/epub+zip"), ".epub")
+	r.Register(NewEmailReader(), nil, ".eml")
+	r.Register(NewMboxReader(), SniffMbox, ".mbox", ".mbx")
+	r.Register(NewCodeReader(), nil, CodeExtensions()...)
+	archives := NewArchiveReader(r)
+	r.Register(archives, SniffTar, ".zip", ".tar", ".tgz", ".gz")
+	r.RegisterSniffer(SniffGzip, archives)
+	r.RegisterFallback(SniffText, NewTextReader())
+	return r
+}
+
+// Register adds rd for the given extensions and, if sniff is non-nil, as a content sniffer.
+func (r *Registry) Register(rd Reader, sniff Sniffer, exts ...string) {
+	for _, ext := range exts {
+		r.RegisterExtension(ext, rd)
+	}
+	if sniff != nil {
+		r.RegisterSniffer(sniff, rd)
+	}
+}
+
+// RegisterExtension maps a file extension (with or without the leading dot) to rd.
+func (r *Registry) RegisterExtension(ext string, rd Reader) {
+	r.byExt[normalizeExt(ext)] = rd
+}
+
+// RegisterSniffer adds a content sniffer. Sniffers run in registration order
+// and take precedence over the file extension.
+func (r *Registry) RegisterSniffer(sniff Sniffer, rd Reader) {
+	r.sniffers = append(r.sniffers, sniffEntry{sniff: sniff, reader: rd})
+}
+
+// RegisterFallback adds a sniffer that is only consulted when neither the
+// content sniffers nor the extension matched.
+func (r *Registry) RegisterFallback(sniff Sniffer, rd Reader) {
+	r.fallbacks = append(r.fallbacks, sniffEntry{sniff: sniff, reader: rd})
+}
+
+// Extensions returns the registered extensions in sorted order.
+func (r *Registry) Extensions() []string {
+	exts := make([]string, 0, len(r.byExt))
+	for ext := range r.byExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// Supports reports whether a reader is registered for the extension of path.
+func (r *Registry) Supports(path string) bool {
+	_, ok := r.byExt[normalizeExt(filepath.Ext(path))]
+	return ok
+}
+
+// Lookup returns the Reader that should handle the file at path.
+func (r *Registry) Lookup(path string) (Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	return r.LookupFrom(f, info.Size(), path)
+}
+
+// LookupFrom returns the Reader for content held in ra, sniffing the content
+// and falling back to the extension of name.
+func (r *Registry) LookupFrom(ra io.ReaderAt, size int64, name string) (Reader, error) {
+	for _, s := range r.sniffers {
+		if s.sniff(ra, size) {
+			return s.reader, nil
+		}
+	}
+	if rd, ok := r.byExt[normalizeExt(filepath.Ext(name))]; ok {
+		return rd, nil
+	}
+	for _, s := range r.fallbacks {
+		if s.sniff(ra, size) {
+			return s.reader, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
+// ExtractAuto picks a Reader for path and extracts its text, cleaned up with
+// NormalizeText.
+func (r *Registry) ExtractAuto(path string) (string, error) {
+	return r.ExtractAutoContext(context.Background(), path)
+}
+
+// ExtractAutoContext is ExtractAuto bound to ctx; see ExtractContext.
+func (r *Registry) ExtractAutoContext(ctx context.Context, path string) (string, error) {
+	rd, err := r.Lookup(path)
+	if err != nil {
+		return "", err
+	}
+	text, err := ExtractContext(ctx, rd, path)
+	if err != nil {
+		return "", err
+	}
+	return NormalizeText(text), nil
+}
+
+// ExtractFrom picks a Reader for the content in ra and extracts its text
+// without it being a file on disk. name supplies the extension when
+// sniffing is inconclusive. Readers that are not StreamReaders are given a
+// temporary copy of the content. The text is cleaned up with NormalizeText.
+func (r *Registry) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	return r.ExtractFromContext(context.Background(), ra, size, name)
+}
+
+// ExtractFromContext is ExtractFrom bound to ctx; see ExtractStreamContext.
+func (r *Registry) ExtractFromContext(ctx context.Context, ra io.ReaderAt, size int64, name string) (string, error) {
+	rd, err := r.LookupFrom(ra, size, name)
+	if err != nil {
+		return "", err
+	}
+	if sr, ok := rd.(StreamReader); ok {
+		text, err := ExtractStreamContext(ctx, sr, ra, size, name)
+		if err != nil {
+			return "", err
+		}
+		return NormalizeText(text), nil
+	}
+
+	tmp, err := os.CreateTemp("", "docai-*"+filepath.Ext(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to buffer %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, io.NewSectionReader(ra, 0, size))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to buffer %s: %w", name, err)
+	}
+	text, err := ExtractContext(ctx, rd, tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	return NormalizeText(text), nil
+}
+
+// ExtractReader reads all of src into memory and extracts its text, for
+// sources such as stdin or an HTTP upload that cannot be read at offsets.
+func (r *Registry) ExtractReader(src io.Reader, name string) (string, error) {
+	return r.ExtractReaderContext(context.Background(), src, name)
+}
+
+// ExtractReaderContext is ExtractReader bound to ctx. Reading src stops at
+// the first read that returns after ctx is done.
+func (r *Registry) ExtractReaderContext(ctx context.Context, src io.Reader, name string) (string, error) {
+	content, err := io.ReadAll(&contextReader{ctx: ctx, r: src})
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return r.ExtractFromContext(ctx, bytes.NewReader(content), int64(len(content)), name)
+}
+
+// contextReader fails reads once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// FileType names the format of a file read by rd, e.g. "pdf" or "docx", so
+// that content picked out by sniffing is named by what it is rather than by
+// its extension. Source code is named by language, and archives and readers
+// outside this package by the file extension.
+func FileType(filePath string, rd Reader) string {
+	switch rd.(type) {
+	case *PDFReader:
+		return "pdf"
+	case *DocxReader:
+		return "docx"
+	case *TextReader:
+		return "txt"
+	case *MarkdownReader:
+		return "md"
+	case *HTMLReader:
+		return "html"
+	case *XLSXReader:
+		return "xlsx"
+	case *CSVReader:
+		if strings.EqualFold(filepath.Ext(filePath), ".tsv") {
+			return "tsv"
+		}
+		return "csv"
+	case *PPTXReader:
+		return "pptx"
+	case *ODTReader:
+		return "odt"
+	case *ODPReader:
+		return "odp"
+	case *EPUBReader:
+		return "epub"
+	case *EmailReader:
+		return "eml"
+	case *MboxReader:
+		return "mbox"
+	case *CodeReader:
+		if lang := CodeLanguage(filePath); lang != "" {
+			return lang
+		}
+	}
+	return strings.TrimPrefix(normalizeExt(filepath.Ext(filePath)), ".")
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// readHead returns up to n bytes from the start of ra.
+func readHead(ra io.ReaderAt, size int64, n int) []byte {
+	if size < int64(n) {
+		n = int(size)
+	}
+	buf := make([]byte, n)
+	read, _ := ra.ReadAt(buf, 0)
+	return buf[:read]
+}
+
+// SniffPDF matches content starting with the %PDF- signature.
+func SniffPDF(ra io.ReaderAt, size int64) bool {
+	return bytes.HasPrefix(readHead(ra, size, 5), []byte("%PDF-"))
+}
+
+// SniffZipEntry returns a Sniffer matching ZIP archives that contain the named entry.
+func SniffZipEntry(name string) Sniffer {
+	return func(ra io.ReaderAt, size int64) bool {
+		if !bytes.HasPrefix(readHead(ra, size, 4), []byte("PK\x03\x04")) {
+			return false
+		}
+		zr, err := zip.NewReader(ra, size)
+		if err != nil {
+			return false
+		}
+		for _, f := range zr.File {
+			if f.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SniffZipMimetype returns a Sniffer matching ZIP containers whose "mimetype"
+// entry holds the given media type, as used by OpenDocument and EPUB files.
+func SniffZipMimetype(mimetype string) Sniffer {
+	return func(ra io.ReaderAt, size int64) bool {
+		if !bytes.HasPrefix(readHead(ra, size, 4), []byte("PK\x03\x04")) {
+			return false
+		}
+		zr, err := zip.NewReader(ra, size)
+		if err != nil {
+			return false
+		}
+		for _, f := range zr.File {
+			if f.Name != "mimetype" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return false
+			}
+			defer rc.Close()
+			content, err := io.ReadAll(io.LimitReader(rc, 256))
+			return err == nil && strings.TrimSpace(string(content)) == mimetype
+		}
+		return false
+	}
+}
+
+// SniffText matches content that looks like text: UTF-8, UTF-16 or UTF-32
+// with a byte order mark, UTF-16 without one, or 8-bit text such as
+// Windows-1252 without control bytes.
+func SniffText(ra io.ReaderAt, size int64) bool {
+	head := readHead(ra, size, 8192)
+	if len(head) == 0 {
+		return true
+	}
+	if charset := DetectCharset(head); strings.HasPrefix(charset, "utf-16") || strings.HasPrefix(charset, "utf-32") {
+		return true
+	}
+	for _, b := range head {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1B {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// zipBytes builds a zip archive holding the given name/content pairs.
+func zipBytes(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeFile writes content to name in a temporary directory and returns its path.
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRegistryLookupFrom(t *testing.T) {
+	reg := NewDefaultRegistry()
+	docx := zipBytes(t, "word/document.xml", "<w:document/>")
+	xlsx := zipBytes(t, "xl/workbook.xml", "<workbook/>")
+	epub := zipBytes(t, "mimetype", "application/epub+zip")
+
+	tests := []struct {
+		name    string
+		file    string
+		content []byte
+		want    Reader
+	}{
+		{"pdf by magic", "upload.bin", []byte("%PDF-1.4\n"), &PDFReader{}},
+		{"docx by entry", "report", docx, &DocxReader{}},
+		{"docx misnamed", "report.txt", docx, &DocxReader{}},
+		{"xlsx by entry", "sheet.dat", xlsx, &XLSXReader{}},
+		{"epub by mimetype", "book.zip", epub, &EPUBReader{}},
+		{"html by content", "page", []byte("<!DOCTYPE html><html></html>"), &HTMLReader{}},
+		{"markdown by extension", "notes.md", []byte("# Title"), &MarkdownReader{}},
+		{"csv by extension", "rows.csv", []byte("a,b\n1,2"), &CSVReader{}},
+		{"code by extension", "main.go", []byte("package main"), &CodeReader{}},
+		{"text fallback", "README", []byte("plain words"), &TextReader{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := reg.LookupFrom(bytes.NewReader(tt.content), int64(len(tt.content)), tt.file)
+			if err != nil {
+				t.Fatalf("LookupFrom: %v", err)
+			}
+			if got, want := FileType(tt.file, rd), FileType(tt.file, tt.want); got != want {
+				t.Errorf("got %T (%s), want %T (%s)", rd, got, tt.want, want)
+			}
+		})
+	}
+}
+
+func TestRegistryLookupUnsupported(t *testing.T) {
+	reg := NewDefaultRegistry()
+	content := []byte{0x00, 0x01, 0x02, 0xff}
+	_, err := reg.LookupFrom(bytes.NewReader(content), int64(len(content)), "blob.bin")
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("got %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestRegistryExtensions(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(NewTextReader(), nil, "TXT", ".log")
+	if got := reg.Extensions(); len(got) != 2 || got[0] != ".log" || got[1] != ".txt" {
+		t.Errorf("Extensions() = %v", got)
+	}
+	if !reg.Supports("/tmp/a.Txt") || reg.Supports("/tmp/a.md") {
+		t.Error("Supports does not match the registered extensions")
+	}
+}
+
+func TestRegistryExtractAuto(t *testing.T) {
+	path := writeFile(t, "note.txt", []byte("caf\xe9 au lait\r\n"))
+	got, err := NewDefaultRegistry().ExtractAuto(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "café au lait\n" {
+		t.Errorf("ExtractAuto() = %q", got)
+	}
+}
+
+func TestRegistryExtractReader(t *testing.T) {
+	got, err := NewDefaultRegistry().ExtractReader(bytes.NewReader([]byte("# Title\n\nBody")), "stdin.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == "" {
+		t.Error("ExtractReader() returned no text")
+	}
+}
+
+func TestRegistryExtractReaderContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	archive := zipBytes(t, "a.txt", "Alpha.")
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		src     []byte
+		file    string
+		want    string
+		wantErr error
+	}{
+		{"text", context.Background(), []byte("Hello."), "stdin.txt", "Hello.", nil},
+		{"archive", context.Background(), archive, "bundle.zip", "Alpha.", nil},
+		{"canceled text", canceled, []byte("Hello."), "stdin.txt", "", context.Canceled},
+		{"canceled archive", canceled, archive, "bundle.zip", "", context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDefaultRegistry().ExtractReaderContext(tt.ctx, bytes.NewReader(tt.src), tt.file)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// plainPageReader is a PageReader and SectionReader without context
+// variants that counts its calls.
+type plainPageReader struct{ calls int }
+
+func (r *plainPageReader) ExtractPages(string) ([]string, error) {
+	r.calls++
+	return []string{"page"}, nil
+}
+
+func (r *plainPageReader) ExtractSections(string) ([]types.Section, error) {
+	r.calls++
+	return []types.Section{{Text: "section"}}, nil
+}
+
+func TestExtractPagesAndSectionsContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		calls   int
+		wantErr error
+	}{
+		{"running", context.Background(), 2, nil},
+		{"canceled", canceled, 0, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := &plainPageReader{}
+			if _, err := ExtractPagesContext(tt.ctx, rd, "doc"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExtractPagesContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := ExtractSectionsContext(tt.ctx, rd, "doc"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExtractSectionsContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if rd.calls != tt.calls {
+				t.Errorf("reader called %d times, want %d", rd.calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package reader
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// textLine is one cleaned line of a structured document.
+type textLine struct {
+	text  string
+	level int // heading level 1-6, or 0 for body text
+}
+
+var blankRun = regexp.MustCompile(`\n{3,}`)
+
+// renderLines joins cleaned lines into plain text, setting headings off with a blank line.
+func renderLines(lines []textLine) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		if l.level > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(l.text)
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(collapseBlankLines(sb.String()))
+}
+
+// groupSections groups body lines under the heading path that precedes them.
+func groupSections(lines []textLine) []types.Section {
+	type heading struct {
+		level int
+		title string
+	}
+	var (
+		stack    []heading
+		body     []string
+		sections []types.Section
+	)
+
+	flush := func() {
+		text := strings.TrimSpace(collapseBlankLines(strings.Join(body, "\n")))
+		body = body[:0]
+		if text == "" {
+			return
+		}
+		path := make([]string, len(stack))
+		for i, h := range stack {
+			path[i] = h.title
+		}
+		sections = append(sections, types.Section{Headings: path, Text: text})
+	}
+
+	for _, l := range lines {
+		if l.level == 0 {
+			body = append(body, l.text)
+			continue
+		}
+		flush()
+		for len(stack) > 0 && stack[len(stack)-1].level >= l.level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, heading{level: l.level, title: l.text})
+	}
+	flush()
+
+	return sections
+}
+
+// collapseBlankLines reduces runs of blank lines to a single blank line.
+func collapseBlankLines(s string) string {
+	return blankRun.ReplaceAllString(s, "\n\n")
+}
@@ -0,0 +1,63 @@
+package reader
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// openFile opens a file for reading through io.ReaderAt and returns its size.
+func openFile(filePath string) (*os.File, int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to stat %s: %w", filePath, err)
+	}
+	return f, info.Size(), nil
+}
+
+// extractFile implements Reader.Extract for a StreamReader.
+func extractFile(sr StreamReader, filePath string) (string, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return sr.ExtractFrom(f, size, filePath)
+}
+
+// readAllAt reads all size bytes behind ra.
+func readAllAt(ra io.ReaderAt, size int64) ([]byte, error) {
+	return io.ReadAll(io.NewSectionReader(ra, 0, size))
+}
+
+// zipParts opens the content behind ra as a zip archive and indexes its
+// entries by name. kind names the format in errors, e.g. "docx".
+func zipParts(ra io.ReaderAt, size int64, name, kind string) (map[string]*zip.File, error) {
+	zipReader, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s file %s as zip: %w", kind, name, err)
+	}
+	parts := make(map[string]*zip.File)
+	for _, file := range zipReader.File {
+		parts[file.Name] = file
+	}
+	return parts, nil
+}
+
+// joinPages joins the non-empty pages of a document with blank lines.
+func joinPages(pages []string) string {
+	var nonEmpty []string
+	for _, p := range pages {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
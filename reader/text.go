@@ -2,10 +2,11 @@ package reader
 
 import (
 	"fmt"
-	"os" // Use ioutil for simplicity, though os.ReadFile is preferred in newer Go
+	"io"
 )
 
 // TextReader implements the Reader interface for plain text files (.txt).
+// The charset is detected with DetectCharset and the text converted to UTF-8.
 type TextReader struct{}
 
 // NewTextReader creates a new TextReader.
@@ -15,9 +16,14 @@ func NewTextReader() *TextReader {
 
 // Extract reads the content of a plain text file.
 func (r *TextReader) Extract(filePath string) (string, error) {
-	content, err := os.ReadFile(filePath) // Use os.ReadFile for Go 1.16+
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads plain text from ra.
+func (r *TextReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	content, err := readAllAt(ra, size)
 	if err != nil {
-		return "", fmt.Errorf("failed to read text file %s: %w", filePath, err)
+		return "", fmt.Errorf("failed to read text file %s: %w", name, err)
 	}
-	return string(content), nil
+	return DecodeText(content), nil
 }
@@ -0,0 +1,262 @@
+package reader
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XLSXReader implements the Reader, PageReader and MetadataReader interfaces
+// for Excel workbooks (.xlsx).
+//
+// Like DocxReader it reads the workbook's zip/XML parts directly. Each sheet
+// is one page; the first non-empty row of a sheet is its header, and every
+// following row becomes a "header: value" record labelled with the sheet name.
+type XLSXReader struct{}
+
+// NewXLSXReader creates a new XLSXReader.
+func NewXLSXReader() *XLSXReader {
+	return &XLSXReader{}
+}
+
+// Extract returns the records of every sheet, separated by blank lines.
+func (r *XLSXReader) Extract(filePath string) (string, error) {
+	return extractFile(r, filePath)
+}
+
+// ExtractFrom reads a workbook from ra and returns the records of every sheet.
+func (r *XLSXReader) ExtractFrom(ra io.ReaderAt, size int64, name string) (string, error) {
+	pages, err := xlsxPages(ra, size, name)
+	if err != nil {
+		return "", err
+	}
+	return joinPages(pages), nil
+}
+
+// ExtractPages returns the records of each sheet, one page per sheet in workbook order.
+func (r *XLSXReader) ExtractPages(filePath string) ([]string, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return xlsxPages(f, size, filePath)
+}
+
+// ExtractMetadata returns the title, author, created and other core
+// properties from docProps/core.xml.
+func (r *XLSXReader) ExtractMetadata(filePath string) (map[string]string, error) {
+	return ooxmlMetadata(filePath, "xlsx")
+}
+
+func xlsxPages(ra io.ReaderAt, size int64, name string) ([]string, error) {
+	parts, err := zipParts(ra, size, name, "xlsx")
+	if err != nil {
+		return nil, err
+	}
+
+	sheets, err := xlsxSheets(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if file, ok := parts["xl/sharedStrings.xml"]; ok {
+		if shared, err = xlsxSharedStrings(file); err != nil {
+			return nil, err
+		}
+	}
+
+	pages := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		file, ok := parts[sheet.part]
+		if !ok {
+			return nil, fmt.Errorf("sheet '%s' (%s) not found in xlsx file %s", sheet.name, sheet.part, name)
+		}
+		rows, err := xlsxRows(file, shared)
+		if err != nil {
+			return nil, err
+		}
+
+		var header []string
+		var records []string
+		for _, row := range rows {
+			if header == nil {
+				header = row
+				continue
+			}
+			if record := formatRecord(header, row); record != "" {
+				records = append(records, "Sheet: "+sheet.name+"\n"+record)
+			}
+		}
+		pages = append(pages, strings.Join(records, "\n\n"))
+	}
+	return pages, nil
+}
+
+type xlsxSheet struct {
+	name string
+	part string // zip path of the worksheet XML
+}
+
+// xlsxSheets lists the worksheets in workbook order, resolving each sheet's
+// relationship ID to its part name.
+func xlsxSheets(parts map[string]*zip.File) ([]xlsxSheet, error) {
+	var workbook struct {
+		Sheets []struct {
+			Name string     `xml:"name,attr"`
+			Attr []xml.Attr `xml:",any,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(parts, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	targets, err := relationshipTargets(parts, "xl/_rels/workbook.xml.rels", "xl")
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]xlsxSheet, 0, len(workbook.Sheets))
+	for i, s := range workbook.Sheets {
+		var relID string
+		for _, a := range s.Attr {
+			if a.Name.Local == "id" {
+				relID = a.Value
+			}
+		}
+		part := fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		if target, ok := targets[relID]; ok {
+			part = target.path
+		}
+		sheets = append(sheets, xlsxSheet{name: s.Name, part: part})
+	}
+	return sheets, nil
+}
+
+// xlsxSharedStrings reads the shared string table, joining rich-text runs.
+func xlsxSharedStrings(file *zip.File) ([]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s' in xlsx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	var table []string
+	var current strings.Builder
+	inPhonetic := false
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return table, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing '%s' in xlsx: %w", file.Name, err)
+		}
+		switch se := token.(type) {
+		case xml.StartElement:
+			switch se.Name.Local {
+			case "si":
+				current.Reset()
+			case "rPh":
+				// Phonetic hints repeat the text in another script.
+				inPhonetic = true
+			case "t":
+				var text string
+				if err := decoder.DecodeElement(&text, &se); err != nil {
+					return nil, fmt.Errorf("error decoding shared string: %w", err)
+				}
+				if !inPhonetic {
+					current.WriteString(text)
+				}
+			}
+		case xml.EndElement:
+			switch se.Name.Local {
+			case "si":
+				table = append(table, current.String())
+			case "rPh":
+				inPhonetic = false
+			}
+		}
+	}
+}
+
+// xlsxRows reads a worksheet into rows of cell text, placing each cell in its
+// column from the cell reference so that sparse rows stay aligned.
+func xlsxRows(file *zip.File, shared []string) ([][]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s' in xlsx: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string   `xml:"t"`
+					Runs []string `xml:"r>t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&sheet); err != nil {
+		return nil, fmt.Errorf("error parsing '%s' in xlsx: %w", file.Name, err)
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		var cells []string
+		for i, c := range row.Cells {
+			col := xlsxColumn(c.Ref)
+			if col < 0 {
+				col = i
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch c.Type {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscanf(c.Value, "%d", &idx); err == nil && idx >= 0 && idx < len(shared) {
+					cells[col] = shared[idx]
+				}
+			case "inlineStr":
+				cells[col] = c.Inline.Text + strings.Join(c.Inline.Runs, "")
+			case "b":
+				cells[col] = map[string]string{"0": "FALSE", "1": "TRUE"}[c.Value]
+			default:
+				cells[col] = c.Value
+			}
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) != "" {
+			rows = append(rows, cells)
+		}
+	}
+	return rows, nil
+}
+
+// xlsxColumn converts the letters of a cell reference such as "AB12" to a
+// zero-based column index, or returns -1 when there are none.
+func xlsxColumn(ref string) int {
+	col := 0
+	n := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXLSXExtractPages(t *testing.T) {
+	const ns = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	workbook := `<workbook ` + ns + `><sheets><sheet name="Stock" sheetId="1" r:id="rId2"/><sheet name="Empty" sheetId="2" r:id="rId1"/></sheets></workbook>`
+	rels := `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet2.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet1.xml"/></Relationships>`
+	shared := `<sst ` + ns + `><si><t>Item</t></si><si><t>Count</t></si><si><r><t>Green </t></r><r><t>tea</t></r><rPh><t>ryokucha</t></rPh></si></sst>`
+	sheet1 := `<worksheet ` + ns + `><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>In stock</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2" t="b"><v>1</v></c></row>
+<row r="3"><c r="B3"><v>7</v></c></row>
+</sheetData></worksheet>`
+	sheet2 := `<worksheet ` + ns + `><sheetData/></worksheet>`
+	file := zipBytes(t,
+		"xl/workbook.xml", workbook,
+		"xl/_rels/workbook.xml.rels", rels,
+		"xl/sharedStrings.xml", shared,
+		"xl/worksheets/sheet1.xml", sheet1,
+		"xl/worksheets/sheet2.xml", sheet2,
+	)
+
+	pages, err := xlsxPages(bytes.NewReader(file), int64(len(file)), "book.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Sheet: Stock\nItem: Green tea\nIn stock: TRUE\n\nSheet: Stock\nCount: 7",
+		"",
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d: %q", len(pages), len(want), pages)
+	}
+	for i := range want {
+		if pages[i] != want[i] {
+			t.Errorf("page %d = %q, want %q", i+1, pages[i], want[i])
+		}
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := map[string]int{"A1": 0, "B7": 1, "Z3": 25, "AA1": 26, "AB12": 27, "12": -1}
+	for ref, want := range tests {
+		if got := xlsxColumn(ref); got != want {
+			t.Errorf("xlsxColumn(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package reader
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Helpers shared by the readers of zip+XML container formats (DOCX, XLSX, PPTX, ODF, EPUB).
+
+// decodeZipXML unmarshals the named zip part into v.
+func decodeZipXML(parts map[string]*zip.File, name string, v any) error {
+	file, ok := parts[name]
+	if !ok {
+		return fmt.Errorf("'%s' not found in archive", name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("error parsing '%s': %w", name, err)
+	}
+	return nil
+}
+
+type relTarget struct {
+	path    string
+	relType string
+}
+
+// relationshipTargets reads an OPC .rels part and resolves each target
+// relative to baseDir, keyed by relationship ID.
+func relationshipTargets(parts map[string]*zip.File, relsName, baseDir string) (map[string]relTarget, error) {
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Type   string `xml:"Type,attr"`
+			Target string `xml:"Target,attr"`
+			Mode   string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(parts, relsName, &rels); err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]relTarget)
+	for _, rel := range rels.Relationships {
+		if rel.Mode == "External" {
+			continue
+		}
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(baseDir, target)
+		}
+		targets[rel.ID] = relTarget{path: target, relType: rel.Type}
+	}
+	return targets, nil
+}
+
+// readZipFile returns the full content of a zip entry.
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s': %w", file.Name, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", file.Name, err)
+	}
+	return content, nil
+}
+
+// coreProperties returns the title, author, dates and other Dublin Core
+// properties of an OOXML document from docProps/core.xml. Documents without
+// the part have no metadata.
+func coreProperties(parts map[string]*zip.File) (map[string]string, error) {
+	meta := make(map[string]string)
+	if _, ok := parts["docProps/core.xml"]; !ok {
+		return meta, nil
+	}
+	var core struct {
+		Title          string `xml:"title"`
+		Subject        string `xml:"subject"`
+		Creator        string `xml:"creator"`
+		Keywords       string `xml:"keywords"`
+		Description    string `xml:"description"`
+		LastModifiedBy string `xml:"lastModifiedBy"`
+		Created        string `xml:"created"`
+		Modified       string `xml:"modified"`
+	}
+	if err := decodeZipXML(parts, "docProps/core.xml", &core); err != nil {
+		return nil, err
+	}
+	for key, value := range map[string]string{
+		"title":            core.Title,
+		"subject":          core.Subject,
+		"author":           core.Creator,
+		"keywords":         core.Keywords,
+		"description":      core.Description,
+		"last_modified_by": core.LastModifiedBy,
+		"created":          core.Created,
+		"modified":         core.Modified,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			meta[key] = value
+		}
+	}
+	return meta, nil
+}
+
+// ooxmlMetadata opens a DOCX, XLSX or PPTX file and returns its core properties.
+func ooxmlMetadata(filePath, kind string) (map[string]string, error) {
+	f, size, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	parts, err := zipParts(f, size, filePath, kind)
+	if err != nil {
+		return nil, err
+	}
+	return coreProperties(parts)
+}
@@ -1,7 +1,9 @@
 package retriever
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	//"github.com/Ashank007/docai/embedder"
 	"github.com/Ashank007/docai/store"
@@ -12,6 +14,15 @@ type CosineRetriever struct {
 	VectorDB   store.VectorStore
 	MetaStore  store.MetadataStore
 	EmbedFunc  func(string) ([]float32, error) // inject embedding logic
+
+	// EmbedContextFunc, when set, embeds the query in RetrieveContext instead
+	// of EmbedFunc, e.g. embedder.OllamaEmbedder.EmbedContext.
+	EmbedContextFunc func(context.Context, string) ([]float32, error)
+
+	// ExpandParents swaps every hit that has a parent chunk for that parent,
+	// keeping each parent once, so that small chunks are matched but the
+	// larger passage around them is returned.
+	ExpandParents bool
 }
 
 func NewCosineRetriever(vdb store.VectorStore, mdb store.MetadataStore, embed func(string) ([]float32, error)) *CosineRetriever {
@@ -23,22 +34,61 @@ func NewCosineRetriever(vdb store.VectorStore, mdb store.MetadataStore, embed fu
 }
 
 func (r *CosineRetriever) Retrieve(query string, topK int,docNameFilter string) ([]types.RetrievedChunk, error) {
-	queryVec, err := r.EmbedFunc(query)
+	return r.RetrieveContext(context.Background(), query, topK, docNameFilter)
+}
+
+// RetrieveContext is Retrieve with the query embedding bound to ctx.
+func (r *CosineRetriever) RetrieveContext(ctx context.Context, query string, topK int, docNameFilter string) ([]types.RetrievedChunk, error) {
+	var queryVec []float32
+	var err error
+	if r.EmbedContextFunc != nil {
+		queryVec, err = r.EmbedContextFunc(ctx, query)
+	} else if err = ctx.Err(); err == nil {
+		queryVec, err = r.EmbedFunc(query)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
 
-	ids, err := r.VectorDB.SearchSimilar(queryVec, topK,docNameFilter)
+	// Several children of the same parent may match, so look further ahead
+	// when hits are merged into their parents.
+	searchK := topK
+	if r.ExpandParents {
+		searchK = topK * 3
+	}
+	ids, err := r.VectorDB.SearchSimilar(queryVec, searchK,docNameFilter)
 	if err != nil {
 		return nil, fmt.Errorf("vector search failed: %w", err)
 	}
 
 	var results []types.RetrievedChunk
+	seen := make(map[string]bool)
 	for _, id := range ids {
+		if len(results) == topK {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		chunk, err := r.MetaStore.GetChunkByID(id)
 		if err != nil {
 			continue
 		}
+		if r.ExpandParents && chunk.ParentID != "" {
+			parentID, err := strconv.ParseInt(chunk.ParentID, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parent of chunk %d: %w", id, err)
+			}
+			parent, err := r.MetaStore.GetChunkByID(parentID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load parent of chunk %d: %w", id, err)
+			}
+			chunk = parent
+		}
+		if seen[chunk.ID] {
+			continue
+		}
+		seen[chunk.ID] = true
 		results = append(results, types.RetrievedChunk{
 			Chunk:     chunk,
 			Embedding: nil, // optional
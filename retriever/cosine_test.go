@@ -0,0 +1,71 @@
+package retriever
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Ashank007/docai/store"
+	"github.com/Ashank007/docai/types"
+)
+
+func TestCosineRetrieverExpandParents(t *testing.T) {
+	ms := store.NewSQLiteStore()
+	if err := ms.Init(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatal(err)
+	}
+	defer ms.Close()
+	vs := store.NewMemoryVectorStore()
+
+	// Parents are stored without vectors; their children point at them.
+	chunks := []struct {
+		chunk  types.Chunk
+		vector []float32
+	}{
+		{types.Chunk{Text: "Parent A."}, nil},
+		{types.Chunk{Text: "A1.", ParentID: "1"}, []float32{1, 0}},
+		{types.Chunk{Text: "A2.", ParentID: "1"}, []float32{0.9, 0.1}},
+		{types.Chunk{Text: "Parent B."}, nil},
+		{types.Chunk{Text: "B1.", ParentID: "4"}, []float32{0.8, 0.2}},
+		{types.Chunk{Text: "Loose."}, []float32{0.7, 0.3}},
+	}
+	for _, c := range chunks {
+		id, err := ms.SaveChunk("doc", c.chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.vector != nil {
+			if err := vs.AddVector(id, c.vector, "doc"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		expand bool
+		topK   int
+		want   []string
+	}{
+		{"children", false, 2, []string{"A1.", "A2."}},
+		{"parents once each", true, 2, []string{"Parent A.", "Parent B."}},
+		{"chunks without parent kept", true, 3, []string{"Parent A.", "Parent B.", "Loose."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewCosineRetriever(vs, ms, func(string) ([]float32, error) { return []float32{1, 0}, nil })
+			r.ExpandParents = tt.expand
+			results, err := r.Retrieve("query", tt.topK, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, rc := range results {
+				got = append(got, rc.Chunk.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
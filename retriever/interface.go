@@ -1,7 +1,18 @@
 package retriever
 
-import "github.com/Ashank007/docai/types"
+import (
+	"context"
+
+	"github.com/Ashank007/docai/types"
+)
 
 type Retriever interface {
   Retrieve(query string, topK int, docNameFilter string) ([]types.RetrievedChunk, error)
 }
+
+// ContextRetriever is implemented by retrievers whose query embedding and
+// lookups can be bound to a context.
+type ContextRetriever interface {
+	Retriever
+	RetrieveContext(ctx context.Context, query string, topK int, docNameFilter string) ([]types.RetrievedChunk, error)
+}
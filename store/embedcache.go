@@ -0,0 +1,166 @@
+package store
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"fmt"
+	"time"
+)
+
+// SQLiteEmbeddingCache stores embeddings in the embedding_cache table, keyed
+// by the embedder's cache key and the SHA-256 of the embedded text, so that unchanged text
+// is not embedded again when a document is re-ingested. It implements
+// embedder.Cache.
+type SQLiteEmbeddingCache struct {
+	db *sql.DB
+}
+
+// CacheStats describes the contents of an embedding cache.
+type CacheStats struct {
+	Entries int64            // cached vectors
+	Bytes   int64            // size of the encoded vectors
+	Hits    int64            // lookups answered from the cache since the entries were stored
+	Keys    map[string]int64 // cached vectors per cache key, e.g. per model
+}
+
+// NewSQLiteEmbeddingCache creates the embedding_cache table in db if needed.
+// The model column holds the cache key.
+func NewSQLiteEmbeddingCache(db *sql.DB) (*SQLiteEmbeddingCache, error) {
+	_, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS embedding_cache (
+		model TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		vector BLOB NOT NULL,
+		created_at TEXT NOT NULL,
+		last_used TEXT NOT NULL,
+		hits INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (model, hash)
+	);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding_cache table: %w", err)
+	}
+	return &SQLiteEmbeddingCache{db: db}, nil
+}
+
+// Get returns the cached vector for a text hash, and records the hit.
+func (c *SQLiteEmbeddingCache) Get(key, hash string) ([]float32, bool, error) {
+	var blob []byte
+	err := c.db.QueryRow(`
+	UPDATE embedding_cache SET hits = hits + 1, last_used = ?
+	WHERE model = ? AND hash = ?
+	RETURNING vector`, cacheTimestamp(time.Now()), key, hash).Scan(&blob)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read embedding cache: %w", err)
+	}
+	var vec []float32
+	if err := gob.NewDecoder(bytes.NewReader(blob)).Decode(&vec); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached embedding: %w", err)
+	}
+	return vec, true, nil
+}
+
+// Put stores the vector for a text hash, replacing any earlier one.
+func (c *SQLiteEmbeddingCache) Put(key, hash string, vec []float32) error {
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(vec); err != nil {
+		return fmt.Errorf("failed to encode embedding: %w", err)
+	}
+	t := cacheTimestamp(time.Now())
+	_, err := c.db.Exec(`
+	INSERT INTO embedding_cache (model, hash, vector, created_at, last_used)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(model, hash) DO UPDATE SET vector = excluded.vector, last_used = excluded.last_used`,
+		key, hash, b.Bytes(), t, t)
+	if err != nil {
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	return nil
+}
+
+// Stats counts the cached vectors, their size and the hits they have served.
+func (c *SQLiteEmbeddingCache) Stats() (CacheStats, error) {
+	stats := CacheStats{Keys: make(map[string]int64)}
+	rows, err := c.db.Query(`
+	SELECT model, COUNT(*), COALESCE(SUM(LENGTH(vector)), 0), COALESCE(SUM(hits), 0)
+	FROM embedding_cache GROUP BY model`)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read embedding cache stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var entries, size, hits int64
+		if err := rows.Scan(&key, &entries, &size, &hits); err != nil {
+			return stats, fmt.Errorf("failed to read embedding cache stats: %w", err)
+		}
+		stats.Keys[key] = entries
+		stats.Entries += entries
+		stats.Bytes += size
+		stats.Hits += hits
+	}
+	return stats, rows.Err()
+}
+
+// Evict removes entries not used since before, then the least recently used
+// entries beyond maxEntries. A zero before or a non-positive maxEntries skips
+// that step. It returns the number of entries removed.
+func (c *SQLiteEmbeddingCache) Evict(before time.Time, maxEntries int) (int64, error) {
+	var removed int64
+	if !before.IsZero() {
+		res, err := c.db.Exec(`DELETE FROM embedding_cache WHERE last_used < ?`, cacheTimestamp(before))
+		if err != nil {
+			return removed, fmt.Errorf("failed to evict embeddings: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	if maxEntries > 0 {
+		res, err := c.db.Exec(`
+		DELETE FROM embedding_cache WHERE rowid IN (
+			SELECT rowid FROM embedding_cache ORDER BY last_used DESC LIMIT -1 OFFSET ?
+		)`, maxEntries)
+		if err != nil {
+			return removed, fmt.Errorf("failed to evict embeddings: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	return removed, nil
+}
+
+// Clear removes every cached vector under key, or all of them when key is empty.
+func (c *SQLiteEmbeddingCache) Clear(key string) (int64, error) {
+	var res sql.Result
+	var err error
+	if key == "" {
+		res, err = c.db.Exec(`DELETE FROM embedding_cache`)
+	} else {
+		res, err = c.db.Exec(`DELETE FROM embedding_cache WHERE model = ?`, key)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear embedding cache: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Vacuum rebuilds the database file to return the space of evicted entries
+// to the file system.
+func (c *SQLiteEmbeddingCache) Vacuum() error {
+	if _, err := c.db.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+// cacheTime is the timestamp format of the cache. Its fixed width keeps
+// timestamps in time order when compared as text.
+const cacheTime = "2006-01-02T15:04:05.000000Z"
+
+func cacheTimestamp(t time.Time) string {
+	return t.UTC().Format(cacheTime)
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T) *SQLiteEmbeddingCache {
+	t.Helper()
+	cache, err := NewSQLiteEmbeddingCache(newTestStore(t).DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+// putAt stores a vector and marks it last used at the given time.
+func putAt(t *testing.T, c *SQLiteEmbeddingCache, key, hash string, used time.Time) {
+	t.Helper()
+	if err := c.Put(key, hash, []float32{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.db.Exec(`UPDATE embedding_cache SET last_used = ? WHERE model = ? AND hash = ?`, cacheTimestamp(used), key, hash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLiteEmbeddingCacheGetPut(t *testing.T) {
+	c := newTestCache(t)
+	if _, ok, err := c.Get("k", "h"); ok || err != nil {
+		t.Fatalf("Get() on an empty cache = %v, %v", ok, err)
+	}
+	if err := c.Put("k", "h", []float32{0.5, -1}); err != nil {
+		t.Fatal(err)
+	}
+	vec, ok, err := c.Get("k", "h")
+	if err != nil || !ok || !reflect.DeepEqual(vec, []float32{0.5, -1}) {
+		t.Errorf("Get() = %v, %v, %v", vec, ok, err)
+	}
+	if _, ok, _ := c.Get("other", "h"); ok {
+		t.Error("vector found under another key")
+	}
+}
+
+func TestSQLiteEmbeddingCacheStats(t *testing.T) {
+	c := newTestCache(t)
+	now := time.Now()
+	putAt(t, c, "ollama:a@http://x", "h1", now)
+	putAt(t, c, "ollama:a@http://x", "h2", now)
+	putAt(t, c, "openai:a/256@http://y", "h1", now)
+	for range 3 {
+		if _, _, err := c.Get("ollama:a@http://x", "h1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 3 || stats.Hits != 3 || stats.Bytes <= 0 {
+		t.Errorf("stats = %+v, want 3 entries and 3 hits", stats)
+	}
+	if want := map[string]int64{"ollama:a@http://x": 2, "openai:a/256@http://y": 1}; !reflect.DeepEqual(stats.Keys, want) {
+		t.Errorf("keys = %v, want %v", stats.Keys, want)
+	}
+}
+
+func TestSQLiteEmbeddingCacheEvict(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		before  time.Time
+		max     int
+		removed int64
+		kept    []string
+	}{
+		{"unused", now.Add(-time.Hour), 0, 2, []string{"new", "recent"}},
+		{"max entries", time.Time{}, 2, 2, []string{"new", "recent"}},
+		{"max keeps most recent", time.Time{}, 1, 3, []string{"new"}},
+		{"both", now.Add(-2 * 24 * time.Hour), 1, 3, []string{"new"}},
+		{"nothing", time.Time{}, 0, 0, []string{"new", "old", "oldest", "recent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(t)
+			putAt(t, c, "k", "oldest", now.Add(-72*time.Hour))
+			putAt(t, c, "k", "old", now.Add(-24*time.Hour))
+			putAt(t, c, "k", "recent", now.Add(-time.Minute))
+			putAt(t, c, "k", "new", now)
+
+			removed, err := c.Evict(tt.before, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if removed != tt.removed {
+				t.Errorf("removed %d, want %d", removed, tt.removed)
+			}
+			var kept []string
+			for _, hash := range []string{"new", "old", "oldest", "recent"} {
+				if _, ok, _ := c.Get("k", hash); ok {
+					kept = append(kept, hash)
+				}
+			}
+			if !reflect.DeepEqual(kept, tt.kept) {
+				t.Errorf("kept %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestSQLiteEmbeddingCacheClear(t *testing.T) {
+	tests := []struct {
+		key     string
+		removed int64
+		left    int64
+	}{
+		{"ollama:a@http://x", 2, 1},
+		{"a", 0, 3},
+		{"", 3, 0},
+	}
+	for _, tt := range tests {
+		c := newTestCache(t)
+		putAt(t, c, "ollama:a@http://x", "h1", time.Now())
+		putAt(t, c, "ollama:a@http://x", "h2", time.Now())
+		putAt(t, c, "openai:a@http://y", "h1", time.Now())
+		removed, err := c.Clear(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := c.Stats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if removed != tt.removed || stats.Entries != tt.left {
+			t.Errorf("Clear(%q) removed %d and left %d, want %d and %d", tt.key, removed, stats.Entries, tt.removed, tt.left)
+		}
+	}
+}
@@ -12,6 +12,25 @@ type MetadataStore interface {
 	Close() error
 }
 
+// FileSaver is implemented by metadata stores that keep a record of every
+// ingested file, such as SQLiteStore, which ListFiles then returns.
+type FileSaver interface {
+	SaveFile(file types.FileMeta) (types.FileMeta, error)
+}
+
+// ChunkBatchSaver is implemented by metadata stores that can save many
+// chunks at once, e.g. in one transaction. IDs are returned in chunk order.
+type ChunkBatchSaver interface {
+	SaveChunks(docName string, chunks []types.Chunk) ([]int64, error)
+}
+
+// VectorBatchAdder is implemented by vector stores that can add many
+// vectors at once, e.g. in one transaction. On error none of the vectors
+// are added.
+type VectorBatchAdder interface {
+	AddVectors(ids []int64, vecs [][]float32, docName string) error
+}
+
 // VectorStore manages vector representations and similarity search
 type VectorStore interface {
 	AddVector(id int64, vec []float32, docName string) error
@@ -2,7 +2,10 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/Ashank007/docai/types"
 	_ "github.com/mattn/go-sqlite3"
@@ -28,13 +31,41 @@ func (s *SQLiteStore) Init(path string) error {
 		doc_name TEXT,
 		chunk_text TEXT,
 		page INT,
-		position INT
+		position INT,
+		meta TEXT,
+		heading_path TEXT,
+		parent_id INTEGER REFERENCES chunks(id)
 	);
 	`
 	_, err = s.db.Exec(stmt)
 	if err != nil {
 		return fmt.Errorf("failed to create chunk table: %w", err)
 	}
+	if err := s.addColumnIfMissing("chunks", "meta", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("chunks", "heading_path", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("chunks", "parent_id", "INTEGER REFERENCES chunks(id)"); err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS documents (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT UNIQUE,
+		path TEXT,
+		file_type TEXT,
+		size INTEGER,
+		hash TEXT,
+		added_at TEXT,
+		pages INTEGER,
+		meta TEXT
+	);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create documents table: %w", err)
+	}
 	stmt2 := `
 		CREATE TABLE IF NOT EXISTS vectors (
 			id INTEGER PRIMARY KEY,
@@ -49,27 +80,162 @@ func (s *SQLiteStore) Init(path string) error {
 	return err
 }
 
+// addColumnIfMissing adds a column to a table created by an older version of the store.
+func (s *SQLiteStore) addColumnIfMissing(table, column, def string) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			dflt       sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &primaryKey); err != nil {
+			return fmt.Errorf("failed to inspect %s table: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def)); err != nil {
+		return fmt.Errorf("failed to add %s column to %s table: %w", column, table, err)
+	}
+	return nil
+}
+
+// SaveFile records an ingested file in the documents table, replacing the
+// record of an earlier ingest under the same name, and returns it with its ID set.
+func (s *SQLiteStore) SaveFile(file types.FileMeta) (types.FileMeta, error) {
+	meta, err := encodeMeta(file.Meta)
+	if err != nil {
+		return file, fmt.Errorf("failed to encode metadata of %s: %w", file.Name, err)
+	}
+	var id int64
+	err = s.db.QueryRow(`
+	INSERT INTO documents (name, path, file_type, size, hash, added_at, pages, meta)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(name) DO UPDATE SET
+		path = excluded.path, file_type = excluded.file_type, size = excluded.size,
+		hash = excluded.hash, added_at = excluded.added_at, pages = excluded.pages,
+		meta = excluded.meta
+	RETURNING id`,
+		file.Name, file.Path, file.FileType, file.Size, file.Hash, file.AddedAt, file.Pages, meta).Scan(&id)
+	if err != nil {
+		return file, fmt.Errorf("failed to save document %s: %w", file.Name, err)
+	}
+	file.ID = fmt.Sprintf("%d", id)
+	return file, nil
+}
+
+const insertChunk = `
+	INSERT INTO chunks (doc_name, chunk_text, page, position, meta, heading_path, parent_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+
 func (s *SQLiteStore) SaveChunk(docName string, chunk types.Chunk) (int64, error) {
-	res, err := s.db.Exec(`
-	INSERT INTO chunks (doc_name, chunk_text, page, position)
-	VALUES (?, ?, ?, ?)`, docName, chunk.Text, chunk.Page, chunk.Position)
+	args, err := chunkArgs(docName, chunk)
+	if err != nil {
+		return 0, err
+	}
+	res, err := s.db.Exec(insertChunk, args...)
 	if err != nil {
 		return 0, err
 	}
 	return res.LastInsertId()
 }
 
+// SaveChunks saves chunks in a single transaction, which is much faster than
+// one SaveChunk call per chunk. Nothing is saved if any chunk fails.
+func (s *SQLiteStore) SaveChunks(docName string, chunks []types.Chunk) ([]int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(insertChunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare chunk insert: %w", err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int64, len(chunks))
+	for i, chunk := range chunks {
+		args, err := chunkArgs(docName, chunk)
+		if err != nil {
+			return nil, err
+		}
+		res, err := stmt.Exec(args...)
+		if err != nil {
+			return nil, err
+		}
+		if ids[i], err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit chunks: %w", err)
+	}
+	return ids, nil
+}
+
+// chunkArgs returns the values of insertChunk for a chunk.
+func chunkArgs(docName string, chunk types.Chunk) ([]any, error) {
+	meta, err := encodeMeta(chunk.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chunk metadata: %w", err)
+	}
+	headings, err := encodeHeadings(chunk.Headings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chunk heading path: %w", err)
+	}
+	var parentID sql.NullInt64
+	if chunk.ParentID != "" {
+		id, err := strconv.ParseInt(chunk.ParentID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent chunk ID %q: %w", chunk.ParentID, err)
+		}
+		parentID = sql.NullInt64{Int64: id, Valid: true}
+	}
+	return []any{docName, chunk.Text, chunk.Page, chunk.Position, meta, headings, parentID}, nil
+}
+
 func (s *SQLiteStore) GetChunkByID(id int64) (types.Chunk, error) {
 	var chunk types.Chunk
+	var meta, headings sql.NullString
+	var parentID sql.NullInt64
 	err := s.db.QueryRow(`
-	SELECT chunk_text, doc_name, page, position FROM chunks WHERE id = ?`, id).
-		Scan(&chunk.Text, &chunk.Source, &chunk.Page, &chunk.Position)
+	SELECT chunk_text, doc_name, page, position, meta, heading_path, parent_id FROM chunks WHERE id = ?`, id).
+		Scan(&chunk.Text, &chunk.Source, &chunk.Page, &chunk.Position, &meta, &headings, &parentID)
 	chunk.ID = fmt.Sprintf("%d", id)
+	if parentID.Valid {
+		chunk.ParentID = fmt.Sprintf("%d", parentID.Int64)
+	}
+	if err == nil {
+		if chunk.Meta, err = decodeMeta(meta); err != nil {
+			return chunk, fmt.Errorf("failed to decode metadata of chunk %d: %w", id, err)
+		}
+		if chunk.Headings, err = decodeHeadings(headings); err != nil {
+			return chunk, fmt.Errorf("failed to decode heading path of chunk %d: %w", id, err)
+		}
+	}
 	return chunk, err
 }
 
+// ListFiles returns every file in the documents table, oldest first. Chunks
+// stored before the table existed are listed by name only.
 func (s *SQLiteStore) ListFiles() ([]types.FileMeta, error) {
-	rows, err := s.db.Query("SELECT DISTINCT doc_name FROM chunks")
+	rows, err := s.db.Query(`
+	SELECT id, name, path, file_type, size, hash, added_at, pages, meta FROM documents ORDER BY id`)
 	if err != nil {
 		return nil, err
 	}
@@ -77,23 +243,132 @@ func (s *SQLiteStore) ListFiles() ([]types.FileMeta, error) {
 
 	var files []types.FileMeta
 	for rows.Next() {
+		var (
+			file                 types.FileMeta
+			id                   int64
+			path, fileType, hash sql.NullString
+			addedAt, meta        sql.NullString
+			size, pages          sql.NullInt64
+		)
+		if err := rows.Scan(&id, &file.Name, &path, &fileType, &size, &hash, &addedAt, &pages, &meta); err != nil {
+			return nil, fmt.Errorf("failed to read documents table: %w", err)
+		}
+		file.ID = fmt.Sprintf("%d", id)
+		file.Path, file.FileType, file.Hash, file.AddedAt = path.String, fileType.String, hash.String, addedAt.String
+		file.Size, file.Pages = size.Int64, int(pages.Int64)
+		if file.Meta, err = decodeMeta(meta); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata of %s: %w", file.Name, err)
+		}
+		files = append(files, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read documents table: %w", err)
+	}
+	rows.Close()
+
+	// Documents inside a file, such as "mail.mbox#2", belong to the file's record.
+	legacy, err := s.db.Query(`
+	SELECT DISTINCT doc_name FROM chunks c WHERE NOT EXISTS (
+		SELECT 1 FROM documents d WHERE c.doc_name = d.name
+			OR substr(c.doc_name, 1, length(d.name) + 1) IN (d.name || '#', d.name || '!')
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	defer legacy.Close()
+	for legacy.Next() {
 		var name string
-		if err := rows.Scan(&name); err != nil {
-			continue
+		if err := legacy.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read chunks table: %w", err)
 		}
 		files = append(files, types.FileMeta{Name: name})
 	}
+	if err := legacy.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chunks table: %w", err)
+	}
 	return files, nil
 }
 
+// encodeMeta stores a metadata map as JSON, or NULL when it is empty.
+func encodeMeta(meta map[string]string) (sql.NullString, error) {
+	if len(meta) == 0 {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+// decodeMeta is the inverse of encodeMeta.
+func decodeMeta(meta sql.NullString) (map[string]string, error) {
+	if !meta.Valid || meta.String == "" {
+		return nil, nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(meta.String), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// encodeHeadings stores a heading path as a JSON array, or NULL when empty.
+func encodeHeadings(headings []string) (sql.NullString, error) {
+	if len(headings) == 0 {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(headings)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
 
+// decodeHeadings is the inverse of encodeHeadings.
+func decodeHeadings(headings sql.NullString) ([]string, error) {
+	if !headings.Valid || headings.String == "" {
+		return nil, nil
+	}
+	var h []string
+	if err := json.Unmarshal([]byte(headings.String), &h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// DeleteFile removes a file's record together with its chunks and vectors,
+// including those of the documents inside it, such as "mail.mbox#2" or
+// "bundle.zip!notes.txt".
 func (s *SQLiteStore) DeleteFile(name string) error {
-	_, err := s.db.Exec(`DELETE FROM vectors WHERE doc_name = ?`, name)
+	tx, err := s.db.Begin()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	_, err = s.db.Exec(`DELETE FROM chunks WHERE doc_name = ?`, name)
-	return err
+	defer tx.Rollback()
+
+	// The same test as ListFiles uses to group documents under their file.
+	const inFile = `doc_name = ? OR substr(doc_name, 1, length(?) + 1) IN (? || '#', ? || '!')`
+	if _, err := tx.Exec(`DELETE FROM vectors WHERE `+inFile, name, name, name, name); err != nil {
+		return fmt.Errorf("failed to delete vectors of %s: %w", name, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM chunks WHERE `+inFile, name, name, name, name); err != nil {
+		return fmt.Errorf("failed to delete chunks of %s: %w", name, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM documents WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to delete document %s: %w", name, err)
+	}
+	return tx.Commit()
+}
+
+// inFile reports whether docName is the file named file or a document inside
+// it, stored as "file#N" or "file!path".
+func inFile(docName, file string) bool {
+	if docName == file {
+		return true
+	}
+	rest, ok := strings.CutPrefix(docName, file)
+	return ok && (rest[0] == '#' || rest[0] == '!')
 }
 
 
@@ -0,0 +1,270 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Ashank007/docai/types"
+)
+
+// newTestStore opens a SQLiteStore on a fresh database file.
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s := NewSQLiteStore()
+	if err := s.Init(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteStoreDeleteFile(t *testing.T) {
+	s := newTestStore(t)
+	vectors, err := NewSQLiteVectorStore(s.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := []string{"mail.mbox", "mail.mbox#1", "mail.mbox#2", "mail.mbox!x", "mail.mboxes", "other.txt"}
+	for _, name := range docs {
+		id, err := s.SaveChunk(name, types.Chunk{Text: "text of " + name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vectors.AddVector(id, []float32{1, 0}, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"mail.mbox", "mail.mboxes", "other.txt"} {
+		if _, err := s.SaveFile(types.FileMeta{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.DeleteFile("mail.mbox"); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := s.DB().Query(`SELECT doc_name FROM chunks UNION ALL SELECT doc_name FROM vectors`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var left []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		left = append(left, name)
+	}
+	sort.Strings(left)
+	want := []string{"mail.mboxes", "mail.mboxes", "other.txt", "other.txt"}
+	if len(left) != len(want) {
+		t.Fatalf("left %v, want %v", left, want)
+	}
+	for i := range want {
+		if left[i] != want[i] {
+			t.Fatalf("left %v, want %v", left, want)
+		}
+	}
+
+	files, err := s.ListFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Name != "mail.mboxes" || files[1].Name != "other.txt" {
+		t.Errorf("ListFiles() = %+v", files)
+	}
+}
+
+func TestDeleteVectorsByDoc(t *testing.T) {
+	stores := map[string]func(t *testing.T) VectorStore{
+		"memory": func(t *testing.T) VectorStore { return NewMemoryVectorStore() },
+		"sqlite": func(t *testing.T) VectorStore {
+			vs, err := NewSQLiteVectorStore(newTestStore(t).DB())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return vs
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			vs := open(t)
+			for i, doc := range []string{"a.zip", "a.zip!b.txt", "a.zip#3", "a.zipper", "b"} {
+				if err := vs.AddVector(int64(i+1), []float32{1}, doc); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := vs.DeleteVectorsByDoc("a.zip"); err != nil {
+				t.Fatal(err)
+			}
+			ids, err := vs.SearchSimilar([]float32{1}, 10, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+				t.Errorf("vectors left: %v, want [4 5]", ids)
+			}
+		})
+	}
+}
+
+func TestAddVectors(t *testing.T) {
+	stores := map[string]func(t *testing.T) (VectorStore, func() VectorStore){
+		"memory": func(t *testing.T) (VectorStore, func() VectorStore) {
+			vs := NewMemoryVectorStore()
+			return vs, func() VectorStore { return vs }
+		},
+		"sqlite": func(t *testing.T) (VectorStore, func() VectorStore) {
+			db := newTestStore(t).DB()
+			vs, err := NewSQLiteVectorStore(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Reopening loads the vectors back from the database.
+			return vs, func() VectorStore {
+				reopened, err := NewSQLiteVectorStore(db)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return reopened
+			}
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			vs, reopen := open(t)
+			ba := vs.(VectorBatchAdder)
+			if err := vs.AddVector(2, []float32{0, 1}, "old"); err != nil {
+				t.Fatal(err)
+			}
+			if err := ba.AddVectors([]int64{1, 2, 3}, [][]float32{{1, 0}, {1, 0}, {1, 0}}, "doc"); err != nil {
+				t.Fatal(err)
+			}
+			if err := ba.AddVectors([]int64{4}, nil, "doc"); err == nil {
+				t.Error("AddVectors() with missing vectors succeeded")
+			}
+			ids, err := reopen().SearchSimilar([]float32{1, 0}, 10, "doc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+				t.Errorf("vectors of doc: %v, want [1 2 3]", ids)
+			}
+		})
+	}
+}
+
+func TestInFile(t *testing.T) {
+	tests := []struct {
+		doc, file string
+		want      bool
+	}{
+		{"mail.mbox", "mail.mbox", true},
+		{"mail.mbox#12", "mail.mbox", true},
+		{"a.zip!dir/b.pdf", "a.zip", true},
+		{"a.zip!in.tar!c.txt", "a.zip", true},
+		{"a.zipx", "a.zip", false},
+		{"a", "a.zip", false},
+	}
+	for _, tt := range tests {
+		if got := inFile(tt.doc, tt.file); got != tt.want {
+			t.Errorf("inFile(%q, %q) = %v, want %v", tt.doc, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSQLiteStoreSaveFile(t *testing.T) {
+	s := newTestStore(t)
+	var _ FileSaver = s
+
+	first := types.FileMeta{Name: "guide", Path: "a/guide.pdf", FileType: "pdf", Size: 10, Hash: "h1", AddedAt: "2024-01-01T00:00:00Z", Pages: 3, Meta: map[string]string{"title": "Guide"}}
+	saved, err := s.SaveFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == "" {
+		t.Error("SaveFile() returned no ID")
+	}
+
+	// Saving the same name again replaces the record but keeps its ID.
+	second := first
+	second.Size, second.Hash, second.Meta = 20, "h2", nil
+	resaved, err := s.SaveFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resaved.ID != saved.ID {
+		t.Errorf("re-saved ID = %s, want %s", resaved.ID, saved.ID)
+	}
+	if _, err := s.SaveChunk("legacy.txt", types.Chunk{Text: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SaveChunk("guide#2", types.Chunk{Text: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := s.ListFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.ID = saved.ID
+	tests := []types.FileMeta{second, {Name: "legacy.txt"}}
+	if len(files) != len(tests) {
+		t.Fatalf("ListFiles() = %+v, want %d files", files, len(tests))
+	}
+	for i, want := range tests {
+		got := files[i]
+		if got.ID != want.ID || got.Name != want.Name || got.Path != want.Path || got.FileType != want.FileType ||
+			got.Size != want.Size || got.Hash != want.Hash || got.AddedAt != want.AddedAt || got.Pages != want.Pages || len(got.Meta) != len(want.Meta) {
+			t.Errorf("file %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestSQLiteStoreChunkRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	tests := []struct {
+		name  string
+		chunk types.Chunk
+	}{
+		{"plain", types.Chunk{Text: "Plain text."}},
+		{"headings", types.Chunk{Text: "Run it.", Page: 2, Position: 40, Headings: []string{"Guide", "Install > Linux"}}},
+		{"meta and parent", types.Chunk{Text: "Child.", Position: 7, ParentID: "1", Meta: map[string]string{"from": "ann@example.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, batch := range []bool{false, true} {
+				var id int64
+				var err error
+				if batch {
+					var ids []int64
+					if ids, err = s.SaveChunks("guide.md", []types.Chunk{tt.chunk}); err == nil {
+						id = ids[0]
+					}
+				} else {
+					id, err = s.SaveChunk("guide.md", tt.chunk)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := s.GetChunkByID(id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := tt.chunk
+				want.ID, want.Source = fmt.Sprintf("%d", id), "guide.md"
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("batch=%v: got %+v, want %+v", batch, got, want)
+				}
+			}
+		})
+	}
+}
@@ -94,6 +94,48 @@ func (s *SQLiteVectorStore) AddVector(id int64, vec []float32, docName string) e
 }
 
 
+// AddVectors adds the vectors of a batch in one transaction.
+func (s *SQLiteVectorStore) AddVectors(ids []int64, vecs [][]float32, docName string) error {
+	if len(ids) != len(vecs) {
+		return fmt.Errorf("got %d vectors for %d ids", len(vecs), len(ids))
+	}
+	blobs := make([][]byte, len(vecs))
+	for i, vec := range vecs {
+		var b bytes.Buffer
+		if err := gob.NewEncoder(&b).Encode(vec); err != nil {
+			return err
+		}
+		blobs[i] = b.Bytes()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO vectors (id, doc_name, vector) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare vector insert: %w", err)
+	}
+	defer stmt.Close()
+	for i, id := range ids {
+		if _, err := stmt.Exec(id, docName, blobs[i]); err != nil {
+			return fmt.Errorf("failed to insert vector: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vectors: %w", err)
+	}
+
+	for i, id := range ids {
+		s.mem[id] = struct{ Vector []float32; DocName string }{Vector: vecs[i], DocName: docName}
+	}
+	return nil
+}
+
 func (s *SQLiteVectorStore) SearchSimilar(query []float32, topK int, docNameFilter string) ([]int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -142,18 +184,22 @@ func (s *SQLiteVectorStore) Reset() error {
 	return err
 }
 
+// DeleteVectorsByDoc removes the vectors of a document and of the documents
+// inside it, such as the messages of a mailbox.
 func (s *SQLiteVectorStore) DeleteVectorsByDoc(docName string) error { // Renamed parameter for clarity
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.Exec(`DELETE FROM vectors WHERE doc_name = ?`, docName)
+	_, err := s.db.Exec(`
+	DELETE FROM vectors WHERE doc_name = ? OR substr(doc_name, 1, length(?) + 1) IN (? || '#', ? || '!')`,
+		docName, docName, docName, docName)
 	if err != nil {
 		return fmt.Errorf("failed to delete vectors from DB for doc %s: %w", docName, err)
 	}
 
 	// remove from memory
 	for id, data := range s.mem {
-		if data.DocName == docName {
+		if inFile(data.DocName, docName) {
 			delete(s.mem, id)
 		}
 	}
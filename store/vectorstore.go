@@ -28,7 +28,25 @@ func NewMemoryVectorStore() *MemoryVectorStore {
 func (m *MemoryVectorStore) AddVector(id int64, vec []float32, docName string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.add(id, vec, docName)
+	return nil
+}
 
+// AddVectors adds the vectors of a batch under one lock.
+func (m *MemoryVectorStore) AddVectors(ids []int64, vecs [][]float32, docName string) error {
+	if len(ids) != len(vecs) {
+		return fmt.Errorf("got %d vectors for %d ids", len(vecs), len(ids))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, id := range ids {
+		m.add(id, vecs[i], docName)
+	}
+	return nil
+}
+
+// add stores a vector, replacing the one with the same ID. m.mu must be held.
+func (m *MemoryVectorStore) add(id int64, vec []float32, docName string) {
 	// Check if ID already exists and update, or append
 	found := false
 	for i, item := range m.data {
@@ -46,8 +64,6 @@ func (m *MemoryVectorStore) AddVector(id int64, vec []float32, docName string) e
 			DocName string
 		}{ID: id, Vector: vec, DocName: docName})
 	}
-
-	return nil
 }
 
 func (m *MemoryVectorStore) SearchSimilar(query []float32, topK int, docNameFilter string) ([]int64, error) {
@@ -101,6 +117,8 @@ func (m *MemoryVectorStore) Reset() error {
 	return nil
 }
 
+// DeleteVectorsByDoc removes the vectors of a document and of the documents
+// inside it, such as the messages of a mailbox.
 func (m *MemoryVectorStore) DeleteVectorsByDoc(docName string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -111,7 +129,7 @@ func (m *MemoryVectorStore) DeleteVectorsByDoc(docName string) error {
 		DocName string
 	}
 	for _, item := range m.data {
-		if item.DocName != docName {
+		if !inFile(item.DocName, docName) {
 			newData = append(newData, item)
 		}
 	}
@@ -2,24 +2,52 @@
 package summarizer
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
-  "path/filepath"
+
 	"github.com/Ashank007/docai/chunker"
 	"github.com/Ashank007/docai/generator"
 	"github.com/Ashank007/docai/reader"
+	"github.com/Ashank007/docai/tokenizer"
 )
 
+// DefaultContextTokens is the default token budget for the document text in
+// one generation request. It fits Ollama's default 2048-token context with
+// room for the prompt and the answer.
+const DefaultContextTokens = 1500
+
 // Summarizer holds the necessary components for document summarization.
+//
+// Documents longer than MaxContextTokens, as counted by Tokenizer, are
+// summarized in parts that each fit the model's context, and the partial
+// summaries are then summarized together.
 type Summarizer struct {
-	Chunker    chunker.Chunker
-	Generator  generator.Generator
+	Chunker          chunker.Chunker
+	Generator        generator.Generator
+	Readers          *reader.Registry
+	Tokenizer        tokenizer.Tokenizer
+	MaxContextTokens int
+	// Output, when set, receives the final summary as a streaming
+	// generator produces it. Partial summaries are never streamed, and nil
+	// streams nothing.
+	Output io.Writer
+
+	// PDFReader, TextReader and DocxReader, when set, read .pdf, .txt and
+	// .docx files in place of Readers.
+	//
+	// Deprecated: register readers in Readers instead.
 	PDFReader  reader.Reader
 	TextReader reader.Reader
 	DocxReader reader.Reader
 }
 
-// NewSummarizer creates and returns a new Summarizer instance.
+// NewSummarizer creates and returns a new Summarizer instance that reads
+// PDF, TXT and DOCX files with the given readers.
+//
+// Deprecated: use NewSummarizerWithRegistry, which supports every format in the registry.
 func NewSummarizer(
 	ch chunker.Chunker,
 	gen generator.Generator,
@@ -27,38 +55,88 @@ func NewSummarizer(
 	textR reader.Reader,
 	docxR reader.Reader,
 ) *Summarizer {
+	reg := reader.NewRegistry()
+	reg.Register(pdfR, reader.SniffPDF, ".pdf")
+	reg.Register(docxR, reader.SniffZipEntry("word/document.xml"), ".docx")
+	reg.Register(textR, nil, ".txt")
+	s := NewSummarizerWithRegistry(ch, gen, reg)
+	s.PDFReader, s.TextReader, s.DocxReader = pdfR, textR, docxR
+	return s
+}
+
+// NewSummarizerWithRegistry creates a Summarizer that picks readers from reg.
+func NewSummarizerWithRegistry(ch chunker.Chunker, gen generator.Generator, reg *reader.Registry) *Summarizer {
 	return &Summarizer{
-		Chunker:    ch,
-		Generator:  gen,
-		PDFReader:  pdfR,
-		TextReader: textR,
-		DocxReader: docxR,
+		Chunker:          ch,
+		Generator:        gen,
+		Readers:          reg,
+		Tokenizer:        tokenizer.NewApproxTokenizer(),
+		MaxContextTokens: DefaultContextTokens,
 	}
 }
 
 // SummarizeDocument reads a document from the given filePath,
 // chunks its content, and uses the LLM to generate a summary.
 func (s *Summarizer) SummarizeDocument(filePath string) (string, error) {
-	var currentReader reader.Reader
-	fileExtension := strings.ToLower(filepath.Ext(filePath))
+	return s.SummarizeDocumentContext(context.Background(), filePath)
+}
+
+// SummarizeDocumentContext is SummarizeDocument bound to ctx. Cancelling ctx
+// stops the summary between, or during, generation requests.
+func (s *Summarizer) SummarizeDocumentContext(ctx context.Context, filePath string) (string, error) {
+	// 1. Read the document content
+	var fullText string
+	var err error
+	if rd := s.legacyReader(filePath); rd != nil {
+		fullText, err = reader.ExtractContext(ctx, rd, filePath)
+		fullText = reader.NormalizeText(fullText)
+	} else if s.Readers != nil {
+		fullText, err = s.Readers.ExtractAutoContext(ctx, filePath)
+	} else {
+		err = fmt.Errorf("%w: %s", reader.ErrUnsupportedFormat, filePath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract text from %s: %w", filePath, err)
+	}
 
-	switch fileExtension {
+	return s.summarize(ctx, fullText)
+}
+
+// legacyReader returns the deprecated per-format reader set for the
+// extension of filePath, or nil.
+func (s *Summarizer) legacyReader(filePath string) reader.Reader {
+	switch strings.ToLower(filepath.Ext(filePath)) {
 	case ".pdf":
-		currentReader = s.PDFReader
+		return s.PDFReader
 	case ".txt":
-		currentReader = s.TextReader
+		return s.TextReader
 	case ".docx":
-		currentReader = s.DocxReader
-	default:
-		return "", fmt.Errorf("unsupported file type: %s", fileExtension)
+		return s.DocxReader
 	}
+	return nil
+}
 
-	// 1. Read the document content
-	fullText, err := currentReader.Extract(filePath)
+// SummarizeFrom summarizes a document read from src, such as an upload or
+// stdin. name is used to pick a reader when the content is not recognised.
+func (s *Summarizer) SummarizeFrom(src io.Reader, name string) (string, error) {
+	return s.SummarizeFromContext(context.Background(), src, name)
+}
+
+// SummarizeFromContext is SummarizeFrom bound to ctx. Cancelling ctx stops
+// reading src, the extraction and the summary.
+func (s *Summarizer) SummarizeFromContext(ctx context.Context, src io.Reader, name string) (string, error) {
+	if s.Readers == nil {
+		return "", fmt.Errorf("failed to extract text from %s: %w", name, reader.ErrUnsupportedFormat)
+	}
+	fullText, err := s.Readers.ExtractReaderContext(ctx, src, name)
 	if err != nil {
-		return "", fmt.Errorf("failed to extract text from %s: %w", filePath, err)
+		return "", fmt.Errorf("failed to extract text from %s: %w", name, err)
 	}
+	return s.summarize(ctx, fullText)
+}
 
+// summarize chunks extracted text and uses the LLM to generate a summary.
+func (s *Summarizer) summarize(ctx context.Context, fullText string) (string, error) {
 	// If the document is empty after extraction, return an appropriate message.
 	if strings.TrimSpace(fullText) == "" {
 		return "The document is empty or contains no extractable text.", nil
@@ -66,7 +144,7 @@ func (s *Summarizer) SummarizeDocument(filePath string) (string, error) {
 
 	// 2. Chunk the document
 	// FIX 1: Handle the error returned by s.Chunker.Chunk
-	chunks, err := s.Chunker.Chunk(fullText) // Now correctly handles both return values
+	chunks, err := chunker.ChunkContext(ctx, s.Chunker, fullText)
 	if err != nil {
 		return "", fmt.Errorf("failed to chunk document: %w", err)
 	}
@@ -89,9 +167,31 @@ func (s *Summarizer) SummarizeDocument(filePath string) (string, error) {
 	// The Generator implementation (e.g., generator/ollama.go) will combine these.
 	prompt := "Please provide a concise and comprehensive summary of the following document. Focus on the main ideas and key information."
 
-	// 5. Send to LLM for summarization
+	// 5. Summarize parts that fit the model's context, then their summaries.
+	for round := 0; ; round++ {
+		batches := s.batches(chunkStrings)
+		// Stop once everything fits, or when the partial summaries no longer pack any tighter.
+		if len(batches) == 1 || (round > 0 && len(batches) == len(chunkStrings)) {
+			break
+		}
+		partials := make([]string, 0, len(batches))
+		for i, batch := range batches {
+			partial, err := generator.GenerateStream(ctx, s.Generator, io.Discard, partPrompt, batch)
+			if err != nil {
+				return "", fmt.Errorf("failed to summarize part %d of %d: %w", i+1, len(batches), err)
+			}
+			partials = append(partials, partial)
+		}
+		chunkStrings = partials
+	}
+
+	// 6. Send to LLM for summarization
 	// FIX 2: Pass both the prompt (query) and the chunkStrings (context) to s.Generator.Generate
-	summary, err := s.Generator.Generate(prompt, chunkStrings) // Now passing two arguments
+	out := s.Output
+	if out == nil {
+		out = io.Discard
+	}
+	summary, err := generator.GenerateStream(ctx, s.Generator, out, prompt, chunkStrings) // Now passing two arguments
 	if err != nil {
 		return "", fmt.Errorf("failed to generate summary: %w", err)
 	}
@@ -99,3 +199,34 @@ func (s *Summarizer) SummarizeDocument(filePath string) (string, error) {
 	return summary, nil
 }
 
+const partPrompt = "Please summarize the following part of a longer document. Keep the main ideas, names, numbers and key information, as the summary will be combined with the summaries of the other parts."
+
+// batches groups consecutive texts into batches of at most MaxContextTokens
+// tokens. A text that is larger on its own makes up a batch by itself.
+func (s *Summarizer) batches(texts []string) [][]string {
+	tok := s.Tokenizer
+	if tok == nil {
+		tok = tokenizer.NewApproxTokenizer()
+	}
+	budget := s.MaxContextTokens
+	if budget <= 0 {
+		budget = DefaultContextTokens
+	}
+
+	var batches [][]string
+	var current []string
+	used := 0
+	for _, text := range texts {
+		n := tok.Count(text)
+		if len(current) > 0 && used+n > budget {
+			batches = append(batches, current)
+			current, used = nil, 0
+		}
+		current = append(current, text)
+		used += n
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
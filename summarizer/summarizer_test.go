@@ -0,0 +1,219 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ashank007/docai/chunker"
+	"github.com/Ashank007/docai/reader"
+)
+
+// fakeGenerator answers every request with a fixed summary and records the
+// contexts it was given.
+type fakeGenerator struct {
+	calls [][]string
+}
+
+func (g *fakeGenerator) Generate(query string, contexts []string) (string, error) {
+	g.calls = append(g.calls, contexts)
+	return "summary", nil
+}
+
+func (g *fakeGenerator) Name() string { return "fake" }
+
+// fixedReader returns the same text for every file.
+type fixedReader string
+
+func (r fixedReader) Extract(path string) (string, error) { return string(r), nil }
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSummarizeDocumentReaders(t *testing.T) {
+	txt := writeFile(t, "doc.txt", "Text from the file.")
+	md := writeFile(t, "doc.md", "# Title\n\nMarkdown from the file.")
+
+	tests := []struct {
+		name    string
+		s       *Summarizer
+		path    string
+		context string // text handed to the generator
+		wantErr bool
+	}{
+		{
+			name:    "registry",
+			s:       NewSummarizerWithRegistry(chunker.NewSentenceChunker(50), &fakeGenerator{}, reader.NewDefaultRegistry()),
+			path:    md,
+			context: "Markdown from the file.",
+		},
+		{
+			name:    "deprecated constructor",
+			s:       NewSummarizer(chunker.NewSentenceChunker(50), &fakeGenerator{}, fixedReader("pdf"), fixedReader("Legacy text reader."), fixedReader("docx")),
+			path:    txt,
+			context: "Legacy text reader.",
+		},
+		{
+			name:    "deprecated field overrides registry",
+			s:       &Summarizer{Chunker: chunker.NewSentenceChunker(50), Generator: &fakeGenerator{}, Readers: reader.NewDefaultRegistry(), TextReader: fixedReader("From the field.")},
+			path:    txt,
+			context: "From the field.",
+		},
+		{
+			name:    "deprecated fields without registry",
+			s:       &Summarizer{Chunker: chunker.NewSentenceChunker(50), Generator: &fakeGenerator{}, TextReader: fixedReader("Only the field.")},
+			path:    txt,
+			context: "Only the field.",
+		},
+		{
+			name:    "no reader for extension",
+			s:       &Summarizer{Chunker: chunker.NewSentenceChunker(50), Generator: &fakeGenerator{}, TextReader: fixedReader("unused")},
+			path:    md,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.SummarizeDocument(tt.path)
+			if tt.wantErr {
+				if !errors.Is(err, reader.ErrUnsupportedFormat) {
+					t.Fatalf("got %v, want ErrUnsupportedFormat", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "summary" {
+				t.Errorf("SummarizeDocument() = %q", got)
+			}
+			calls := tt.s.Generator.(*fakeGenerator).calls
+			if len(calls) != 1 || !strings.Contains(strings.Join(calls[0], " "), tt.context) {
+				t.Errorf("generator got %q, want it to contain %q", calls, tt.context)
+			}
+		})
+	}
+}
+
+func TestSummarizeMapReduce(t *testing.T) {
+	gen := &fakeGenerator{}
+	s := NewSummarizerWithRegistry(chunker.NewSentenceChunker(20), gen, reader.NewDefaultRegistry())
+	s.MaxContextTokens = 40
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog again and again. ", 20)
+
+	if _, err := s.SummarizeFrom(strings.NewReader(text), "long.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if len(gen.calls) < 3 {
+		t.Fatalf("got %d generation requests, want partial summaries and a final one", len(gen.calls))
+	}
+	final := gen.calls[len(gen.calls)-1]
+	for _, c := range final {
+		if c != "summary" {
+			t.Errorf("final request got %q, want only partial summaries", c)
+		}
+	}
+}
+
+// streamGenerator is a StreamGenerator that streams its answer, which names
+// the prompt it was given, to the writer it is handed.
+type streamGenerator struct{ fakeGenerator }
+
+func (g *streamGenerator) GenerateContext(ctx context.Context, query string, contexts []string) (string, error) {
+	return g.GenerateStream(ctx, os.Stdout, query, contexts)
+}
+
+func (g *streamGenerator) GenerateStream(_ context.Context, w io.Writer, query string, contexts []string) (string, error) {
+	answer := "final "
+	if query == partPrompt {
+		answer = "part "
+	}
+	g.calls = append(g.calls, contexts)
+	fmt.Fprint(w, answer)
+	return answer, nil
+}
+
+func TestSummarizeStreamsFinalSummary(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog again and again. ", 20)
+	tests := []struct {
+		name   string
+		output bool
+		want   string
+	}{
+		{"no output", false, ""},
+		{"output", true, "final "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &streamGenerator{}
+			s := NewSummarizerWithRegistry(chunker.NewSentenceChunker(20), gen, reader.NewDefaultRegistry())
+			s.MaxContextTokens = 40
+			var out strings.Builder
+			if tt.output {
+				s.Output = &out
+			}
+			if _, err := s.SummarizeFrom(strings.NewReader(text), "long.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if len(gen.calls) < 3 {
+				t.Fatalf("got %d generation requests, want partial summaries and a final one", len(gen.calls))
+			}
+			// Partial summaries are collected without being echoed.
+			if out.String() != tt.want {
+				t.Errorf("streamed %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	gen := &fakeGenerator{}
+	s := NewSummarizerWithRegistry(chunker.NewSentenceChunker(20), gen, reader.NewDefaultRegistry())
+	got, err := s.SummarizeFrom(strings.NewReader("  \n"), "empty.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gen.calls) != 0 || !strings.Contains(got, "empty") {
+		t.Errorf("SummarizeFrom() = %q after %d requests", got, len(gen.calls))
+	}
+}
+
+func TestSummarizeFromContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"live", context.Background(), nil},
+		{"canceled", canceled, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{}
+			s := NewSummarizerWithRegistry(chunker.NewSentenceChunker(20), gen, reader.NewDefaultRegistry())
+			got, err := s.SummarizeFromContext(tt.ctx, strings.NewReader("# Notes\n\nSome text."), "stdin.md")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(gen.calls) != 0 {
+				t.Errorf("generator called %d times after cancellation", len(gen.calls))
+			}
+			if tt.wantErr == nil && got != "summary" {
+				t.Errorf("got %q, want %q", got, "summary")
+			}
+		})
+	}
+}
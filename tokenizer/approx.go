@@ -0,0 +1,46 @@
+package tokenizer
+
+import "unicode/utf8"
+
+// ApproxTokenizer estimates token counts without a vocab. It splits text
+// the way BPE tokenizers do and counts one token per CharsPerToken ASCII
+// characters of each piece and one per non-ASCII character, which errs on
+// the high side for English text.
+type ApproxTokenizer struct {
+	CharsPerToken int
+}
+
+// NewApproxTokenizer creates an ApproxTokenizer that assumes four ASCII
+// characters per token.
+func NewApproxTokenizer() *ApproxTokenizer {
+	return &ApproxTokenizer{CharsPerToken: 4}
+}
+
+// Count returns the estimated number of tokens in text.
+func (t *ApproxTokenizer) Count(text string) int {
+	perToken := t.CharsPerToken
+	if perToken <= 0 {
+		perToken = 4
+	}
+	n := 0
+	for _, piece := range pretokenize(text) {
+		ascii, other := 0, 0
+		for _, r := range piece {
+			if r < utf8.RuneSelf {
+				ascii++
+			} else {
+				other++
+			}
+		}
+		// A leading space is merged into the word after it.
+		if ascii > 1 && piece[0] == ' ' {
+			ascii--
+		}
+		n += (ascii+perToken-1)/perToken + other
+	}
+	return n
+}
+
+func (t *ApproxTokenizer) Name() string {
+	return "approx"
+}
@@ -0,0 +1,185 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BPETokenizer is a byte-level BPE tokenizer that runs offline from a vocab
+// file in the tiktoken format: one base64-encoded token and its rank per
+// line. This is the format of cl100k_base.tiktoken and of the
+// tokenizer.model shipped with Llama 3 models. Special tokens are not
+// recognised; they are encoded as ordinary text.
+type BPETokenizer struct {
+	ranks   map[string]int
+	decoder map[int]string
+
+	mu    sync.Mutex
+	cache map[string][]int // encoded pieces, which repeat often in natural text
+}
+
+// maxCachedPieces bounds the piece cache of a BPETokenizer.
+const maxCachedPieces = 1 << 16
+
+// LoadBPE reads a tiktoken vocab file.
+func LoadBPE(path string) (*BPETokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vocab file: %w", err)
+	}
+	defer f.Close()
+	t, err := NewBPETokenizer(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vocab file %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// NewBPETokenizer reads a vocab in the tiktoken format from r.
+func NewBPETokenizer(r io.Reader) (*BPETokenizer, error) {
+	t := &BPETokenizer{
+		ranks:   make(map[string]int),
+		decoder: make(map[int]string),
+		cache:   make(map[string][]int),
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a token and a rank", line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid token: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rank: %w", line, err)
+		}
+		t.ranks[string(token)] = rank
+		t.decoder[rank] = string(token)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.ranks) == 0 {
+		return nil, fmt.Errorf("vocab is empty")
+	}
+	return t, nil
+}
+
+// Encode returns the token IDs of text. Bytes missing from the vocab, which
+// a complete byte-level vocab never has, are encoded as -1.
+func (t *BPETokenizer) Encode(text string) []int {
+	var ids []int
+	for _, piece := range pretokenize(text) {
+		ids = append(ids, t.encodePiece(piece)...)
+	}
+	return ids
+}
+
+// Decode returns the text of the token IDs.
+func (t *BPETokenizer) Decode(ids []int) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		sb.WriteString(t.decoder[id])
+	}
+	return sb.String()
+}
+
+// Count returns the number of tokens in text.
+func (t *BPETokenizer) Count(text string) int {
+	n := 0
+	for _, piece := range pretokenize(text) {
+		n += len(t.encodePiece(piece))
+	}
+	return n
+}
+
+func (t *BPETokenizer) Name() string {
+	return "bpe"
+}
+
+func (t *BPETokenizer) encodePiece(piece string) []int {
+	if rank, ok := t.ranks[piece]; ok {
+		return []int{rank}
+	}
+
+	t.mu.Lock()
+	ids, ok := t.cache[piece]
+	t.mu.Unlock()
+	if ok {
+		return ids
+	}
+
+	ids = t.merge(piece)
+
+	t.mu.Lock()
+	if len(t.cache) >= maxCachedPieces {
+		clear(t.cache)
+	}
+	t.cache[piece] = ids
+	t.mu.Unlock()
+	return ids
+}
+
+// merge applies byte-pair merges to piece, always merging the adjacent pair
+// whose concatenation has the lowest rank, until no pair is in the vocab.
+func (t *BPETokenizer) merge(piece string) []int {
+	// bounds[i] is the start of the i-th part; the last entry is len(piece).
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	pairRank := func(i int) int {
+		if i+2 >= len(bounds) {
+			return math.MaxInt
+		}
+		if rank, ok := t.ranks[piece[bounds[i]:bounds[i+2]]]; ok {
+			return rank
+		}
+		return math.MaxInt
+	}
+
+	ranks := make([]int, len(bounds)-1)
+	for i := range ranks {
+		ranks[i] = pairRank(i)
+	}
+	for len(bounds) > 2 {
+		best := 0
+		for i, rank := range ranks[:len(bounds)-2] {
+			if rank < ranks[best] {
+				best = i
+			}
+		}
+		if ranks[best] == math.MaxInt {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+		ranks = append(ranks[:best+1], ranks[best+2:]...)
+		ranks[best] = pairRank(best)
+		if best > 0 {
+			ranks[best-1] = pairRank(best - 1)
+		}
+	}
+
+	ids := make([]int, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		if rank, ok := t.ranks[piece[bounds[i]:bounds[i+1]]]; ok {
+			ids = append(ids, rank)
+		} else {
+			ids = append(ids, -1)
+		}
+	}
+	return ids
+}
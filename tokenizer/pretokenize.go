@@ -0,0 +1,107 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pretokenize splits text into the pieces that BPE merges are applied
+// within. It follows the split pattern of the cl100k_base and Llama 3
+// tokenizers:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// which Go's regexp package cannot express because of the lookahead.
+func pretokenize(text string) []string {
+	var pieces []string
+	for i := 0; i < len(text); {
+		n := pieceLen(text[i:])
+		pieces = append(pieces, text[i:i+n])
+		i += n
+	}
+	return pieces
+}
+
+var contractions = []string{"'s", "'t", "'re", "'ve", "'m", "'ll", "'d"}
+
+// pieceLen returns the byte length of the piece at the start of s, trying
+// the alternatives of the split pattern in order.
+func pieceLen(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+
+	if r == '\'' {
+		for _, c := range contractions {
+			if len(s) >= len(c) && strings.EqualFold(s[:len(c)], c) {
+				return len(c)
+			}
+		}
+	}
+
+	// [^\r\n\p{L}\p{N}]?\p{L}+
+	if unicode.IsLetter(r) {
+		return size + runLen(s[size:], unicode.IsLetter)
+	}
+	if r != '\r' && r != '\n' && !unicode.IsNumber(r) {
+		if next, _ := utf8.DecodeRuneInString(s[size:]); unicode.IsLetter(next) {
+			return size + runLen(s[size:], unicode.IsLetter)
+		}
+	}
+
+	// \p{N}{1,3}
+	if unicode.IsNumber(r) {
+		n := size
+		for count := 1; count < 3 && n < len(s); count++ {
+			next, nsize := utf8.DecodeRuneInString(s[n:])
+			if !unicode.IsNumber(next) {
+				break
+			}
+			n += nsize
+		}
+		return n
+	}
+
+	// ?[^\s\p{L}\p{N}]+[\r\n]*
+	start := 0
+	if r == ' ' {
+		start = size
+	}
+	if n := runLen(s[start:], isSymbol); n > 0 {
+		n += start
+		return n + runLen(s[n:], isNewline)
+	}
+
+	// \s*[\r\n]+ matches up to the last newline of the whitespace run.
+	ws := runLen(s, unicode.IsSpace)
+	if last := strings.LastIndexAny(s[:ws], "\r\n"); last >= 0 {
+		return last + 1
+	}
+
+	// \s+(?!\S) leaves the last space of a run to the word after it.
+	if ws > size && ws < len(s) {
+		_, lastSize := utf8.DecodeLastRuneInString(s[:ws])
+		return ws - lastSize
+	}
+	if ws > 0 {
+		return ws
+	}
+	return size
+}
+
+// runLen returns the byte length of the longest prefix of s whose runes all satisfy f.
+func runLen(s string, f func(rune) bool) int {
+	for i, r := range s {
+		if !f(r) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func isNewline(r rune) bool {
+	return r == '\r' || r == '\n'
+}
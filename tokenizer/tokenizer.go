@@ -0,0 +1,22 @@
+// Package tokenizer counts the tokens a language model sees in a piece of
+// text, so that chunks and prompts can be kept within the model's context.
+package tokenizer
+
+// Tokenizer counts the model tokens in text.
+type Tokenizer interface {
+	Count(text string) int
+	Name() string
+}
+
+// Load returns the BPE tokenizer in the vocab file at path or, when path is
+// empty or cannot be loaded, an ApproxTokenizer together with the load error.
+func Load(path string) (Tokenizer, error) {
+	if path == "" {
+		return NewApproxTokenizer(), nil
+	}
+	bpe, err := LoadBPE(path)
+	if err != nil {
+		return NewApproxTokenizer(), err
+	}
+	return bpe, nil
+}
@@ -0,0 +1,133 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testVocab is a byte-level vocab: every single byte, then a few merges.
+func testVocab() string {
+	var sb strings.Builder
+	for b := 0; b < 256; b++ {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), b)
+	}
+	for i, merge := range []string{"he", "ll", "llo", "hello", " w", "or", " wor", " world"} {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), 256+i)
+	}
+	return sb.String()
+}
+
+func TestBPETokenizer(t *testing.T) {
+	tok, err := NewBPETokenizer(strings.NewReader(testVocab()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text string
+		ids  []int
+	}{
+		{"hello", []int{259}},
+		{"hell", []int{256, 257}},
+		{"hello world", []int{259, 263}},
+		{" wort", []int{262, 't'}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := tok.Encode(tt.text); !reflect.DeepEqual(got, tt.ids) {
+			t.Errorf("Encode(%q) = %v, want %v", tt.text, got, tt.ids)
+		}
+	}
+
+	for _, text := range []string{
+		"hello world",
+		"I'm here, 12345 ok\n\n",
+		"naïve café — 日本語のテキスト 🎉",
+		"tabs\tand  double  spaces \r\n",
+		"",
+	} {
+		ids := tok.Encode(text)
+		if got := tok.Decode(ids); got != text {
+			t.Errorf("Decode(Encode(%q)) = %q", text, got)
+		}
+		if n := tok.Count(text); n != len(ids) {
+			t.Errorf("Count(%q) = %d, want %d", text, n, len(ids))
+		}
+	}
+}
+
+func TestNewBPETokenizerErrors(t *testing.T) {
+	tests := []struct {
+		name, vocab string
+	}{
+		{"empty", ""},
+		{"missing rank", "aGk=\n"},
+		{"bad base64", "!!! 1\n"},
+		{"bad rank", "aGk= one\n"},
+	}
+	for _, tt := range tests {
+		if _, err := NewBPETokenizer(strings.NewReader(tt.vocab)); err == nil {
+			t.Errorf("%s: NewBPETokenizer succeeded", tt.name)
+		}
+	}
+}
+
+func TestPretokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"I'm here, 12345 ok", []string{"I", "'m", " here", ",", " ", "123", "45", " ok"}},
+		{"Hello  world\n\nBye", []string{"Hello", " ", " world", "\n\n", "Bye"}},
+		{"x = y+1;", []string{"x", " =", " y", "+", "1", ";"}},
+	}
+	for _, tt := range tests {
+		if got := pretokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pretokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestApproxTokenizer(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 4},
+		{"a b c", 3},
+		{"日本語", 3},
+	}
+	tok := NewApproxTokenizer()
+	for _, tt := range tests {
+		if got := tok.Count(tt.text); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vocab.tiktoken")
+	if err := os.WriteFile(path, []byte(testVocab()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path    string
+		name    string
+		wantErr bool
+	}{
+		{"", "approx", false},
+		{path, "bpe", false},
+		{filepath.Join(t.TempDir(), "missing"), "approx", true},
+	}
+	for _, tt := range tests {
+		tok, err := Load(tt.path)
+		if (err != nil) != tt.wantErr || tok.Name() != tt.name {
+			t.Errorf("Load(%q) = %s, %v", tt.path, tok.Name(), err)
+		}
+	}
+}
@@ -4,9 +4,12 @@ package types
 type Chunk struct {
 	ID       string // optional: unique ID or hash
 	Text     string
-	Source   string // filename or origin
-	Page     int    // optional page number if from PDF
-	Position int    // optional position/index in document
+	Source   string            // filename or origin
+	Page     int               // optional page number if from PDF
+	Position int               // optional: character (rune) offset of the chunk in the page or section text it was cut from
+	Headings []string          // optional: heading path of the section the chunk came from
+	ParentID string            // optional: ID of the larger chunk this one was cut from
+	Meta     map[string]string // optional: source-specific fields, e.g. an email's "from" and "subject"
 }
 
 // ChunkWithEmbedding binds a chunk to its vector representation
@@ -24,9 +27,28 @@ type RetrievedChunk struct {
 
 // FileMeta stores info about added files in the system
 type FileMeta struct {
-	ID       string // unique file ID
-	Name     string // file name
-	Path     string // absolute or relative path
-	AddedAt  string // timestamp in RFC3339 format
-	FileType string // e.g., pdf, txt
+	ID       string            // unique file ID
+	Name     string            // file name
+	Path     string            // absolute or relative path
+	AddedAt  string            // timestamp in RFC3339 format
+	FileType string            // e.g., pdf, txt
+	Size     int64             // size in bytes
+	Hash     string            // hex-encoded SHA-256 of the file content
+	Pages    int               // page count, 0 for formats without pages
+	Meta     map[string]string // reader-extracted metadata, e.g. a PDF's title and author
+}
+
+// Section is a block of document text together with the headings above it.
+type Section struct {
+	Headings []string // heading path from the top-level heading down, e.g. ["Guide", "Install"]
+	Text     string
+	Page     int // optional: page or chapter the section is on, 0 if unknown
+}
+
+// Document is one logical document inside a file. Container formats such as
+// mailboxes hold several, each stored under its own name.
+type Document struct {
+	Name string // identifies the document within its file, e.g. a message number
+	Text string
+	Meta map[string]string // document-level metadata, copied onto every chunk
 }